@@ -141,3 +141,12 @@ func TestCombine(t *testing.T) {
 		t.Errorf("Combined Max returned %v, expected 5", resultSlice[3])
 	}
 }
+
+func TestList(t *testing.T) {
+	// Tests collecting values into a slice
+	result := aggregate.List()("a", "b", "c")
+	list, ok := result.([]any)
+	if !ok || len(list) != 3 || list[0] != "a" || list[2] != "c" {
+		t.Errorf("List aggregator returned %v, expected [a b c]", result)
+	}
+}
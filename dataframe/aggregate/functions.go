@@ -275,3 +275,12 @@ func Last() Aggregator {
 		return values[len(values)-1]
 	}
 }
+
+// List returns an aggregator that collects the values into a slice
+func List() Aggregator {
+	return func(values ...any) any {
+		list := make([]any, len(values))
+		copy(list, values)
+		return list
+	}
+}
@@ -4,17 +4,31 @@ import (
 	"fmt"
 	"sort"
 	"teddy/dataframe"
+	"teddy/dataframe/internal/parallel"
 	"teddy/dataframe/series"
 )
 
 // GroupBy groups data by one or more columns and applies aggregation functions to other columns
-// Returns a new DataFrame with results
-func GroupBy(df *dataframe.DataFrame, by []string, aggregations map[string]Aggregator) *dataframe.DataFrame {
-	// Check if all groupby columns exist
-	for _, col := range by {
-		if !df.HasColumn(col) {
-			return dataframe.NewDataFrame()
-		}
+// Returns a new DataFrame with results, or with an error wrapping ErrColumnNotFound if a group
+// column doesn't exist. A DataFrame with an error is returned as it is.
+//
+// The group columns can be given as anything DataFrame.GetColumnNames accepts: a name,
+// a []string, a ColumnSelector such as ByRegex or ByType, or a []any mixing them.
+//
+// The rows and groups are split across the goroutines set by the Parallelism of the DataFrame,
+// so with more than one the aggregators must be safe to call concurrently.
+//
+// Options:
+//   - index: bool (default: false) If true, the group columns become the index of the result, so groups can be found with Loc.
+func GroupBy(df *dataframe.DataFrame, columns any, aggregations map[string]Aggregator, options ...dataframe.OptionsMap) *dataframe.DataFrame {
+	if df.Err() != nil {
+		return df
+	}
+
+	// Resolve the group columns, checking that they exist
+	by, err := df.GetColumnNames(columns)
+	if err != nil {
+		return dataframe.NewDataFrame().WithError(err)
 	}
 
 	// Get the values for groupby columns
@@ -25,13 +39,18 @@ func GroupBy(df *dataframe.DataFrame, by []string, aggregations map[string]Aggre
 	}
 
 	// Group data by constructing composite keys
-	groupData := make(map[string][]int)
-	for i := 0; i < df.Height(); i++ {
-		key := ""
-		for j := range by {
-			key += fmt.Sprintf("%v|", groupKeys[j][i])
+	rowKeys := make([]string, df.Height())
+	parallel.Run(parallel.Ranges(df.Height(), df.Parallelism()), func(_, start, end int) {
+		for i := start; i < end; i++ {
+			key := ""
+			for j := range by {
+				key += fmt.Sprintf("%v|", groupKeys[j][i])
+			}
+			rowKeys[i] = key
 		}
-
+	})
+	groupData := make(map[string][]int)
+	for i, key := range rowKeys {
 		groupData[key] = append(groupData[key], i)
 	}
 
@@ -67,7 +86,7 @@ func GroupBy(df *dataframe.DataFrame, by []string, aggregations map[string]Aggre
 
 	// Add group columns to result
 	for _, col := range by {
-		result.AddSeries(series.NewSeries(col, groupValues[col]))
+		result = result.AddSeries(series.NewSeries(col, groupValues[col]))
 	}
 
 	// Process all aggregations first
@@ -102,27 +121,36 @@ func GroupBy(df *dataframe.DataFrame, by []string, aggregations map[string]Aggre
 		}
 
 		s := df.GetSeries(aggColName)
-		aggValues := make([]any, 0, len(keys))
-
-		for _, key := range keys {
-			rows := groupData[key]
-			// Extract values for this group
-			values := make([]any, 0, len(rows))
-			for _, row := range rows {
-				values = append(values, s.Get(row))
+		aggValues := make([]any, len(keys))
+
+		parallel.Run(parallel.Ranges(len(keys), df.Parallelism()), func(_, start, end int) {
+			for i, key := range keys[start:end] {
+				rows := groupData[key]
+				// Extract values for this group
+				values := make([]any, 0, len(rows))
+				for _, row := range rows {
+					values = append(values, s.Get(row))
+				}
+
+				// Apply the aggregation function
+				aggValues[start+i] = agg(values...)
 			}
-
-			// Apply the aggregation function
-			aggValues = append(aggValues, agg(values...))
-		}
+		})
 
 		processedAggs[aggColName] = aggValues
 	}
 
 	// Now add all processed aggregations to the result
 	for colName, values := range processedAggs {
-		result.AddSeries(series.NewSeries(colName, values))
+		result = result.AddSeries(series.NewSeries(colName, values))
 	}
 
+	var option dataframe.OptionsMap
+	if len(options) > 0 {
+		option = options[0]
+	}
+	if option.Get("index", false).(bool) {
+		result = result.SetIndex(by...)
+	}
 	return result
 }
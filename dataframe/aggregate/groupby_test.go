@@ -1,6 +1,8 @@
 package aggregate_test
 
 import (
+	"errors"
+	"slices"
 	"teddy/dataframe"
 	"teddy/dataframe/aggregate"
 	"teddy/dataframe/series"
@@ -212,3 +214,63 @@ func TestGroupByWithDifferentAggregationTypes(t *testing.T) {
 		t.Errorf("Expected mean of category C to be 240.0, got %f", categoryToMean["C"])
 	}
 }
+
+func TestGroupByParallel(t *testing.T) {
+	// Tests that a parallel GroupBy gives the same result as a sequential one
+	sequential := aggregate.GroupBy(createTestDataFrame(), []string{"category", "region"}, map[string]aggregate.Aggregator{"sales": aggregate.Sum()})
+	parallel := aggregate.GroupBy(createTestDataFrame().SetParallelism(3), []string{"category", "region"}, map[string]aggregate.Aggregator{"sales": aggregate.Sum()})
+
+	for _, column := range []string{"category", "region", "sales"} {
+		if !slices.Equal(sequential.GetSeries(column).Values(), parallel.GetSeries(column).Values()) {
+			t.Errorf("%s: expected %v, got %v", column, sequential.GetSeries(column).Values(), parallel.GetSeries(column).Values())
+		}
+	}
+}
+
+func TestGroupByIndex(t *testing.T) {
+	// Tests that the groups can be found by their keys with the index option
+	result := aggregate.GroupBy(createTestDataFrame(), []string{"category"}, map[string]aggregate.Aggregator{"sales": aggregate.Sum()},
+		dataframe.OptionsMap{"index": true})
+
+	if !slices.Equal(result.IndexNames(), []string{"category"}) || result.HasColumn("category") {
+		t.Fatalf("Expected category to be the index, got index %v and columns %v", result.IndexNames(), result.ColumnNames())
+	}
+	if sales := result.Loc("B").GetSeries("sales").Values(); !slices.Equal(sales, []any{450}) {
+		t.Errorf("Expected sales [450] for B, got %v", sales)
+	}
+}
+
+func TestGroupByErrors(t *testing.T) {
+	// Tests that a missing group column and an input error are reported through Err
+	aggregations := map[string]aggregate.Aggregator{"sales": aggregate.Sum()}
+
+	result := aggregate.GroupBy(createTestDataFrame(), []string{"missing"}, aggregations)
+	if !errors.Is(result.Err(), dataframe.ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", result.Err())
+	}
+
+	errored := createTestDataFrame().Select("missing")
+	result = aggregate.GroupBy(errored, []string{"category"}, aggregations)
+	if result.Err() == nil || result.Err() != errored.Err() {
+		t.Errorf("Expected the error of the input, got %v", result.Err())
+	}
+}
+
+func TestGroupBySelector(t *testing.T) {
+	// Tests that the group columns can be given as selectors
+	aggregations := map[string]aggregate.Aggregator{"sales": aggregate.Sum()}
+
+	byName := aggregate.GroupBy(createTestDataFrame(), []string{"category", "region"}, aggregations)
+	bySelector := aggregate.GroupBy(createTestDataFrame(), dataframe.ByType("string"), aggregations)
+	if bySelector.Err() != nil {
+		t.Fatalf("Error grouping by selector: %v", bySelector.Err())
+	}
+	if !slices.Equal(bySelector.ColumnNames(), byName.ColumnNames()) || bySelector.Height() != byName.Height() {
+		t.Errorf("Expected columns %v and %d rows, got %v and %d", byName.ColumnNames(), byName.Height(), bySelector.ColumnNames(), bySelector.Height())
+	}
+
+	result := aggregate.GroupBy(createTestDataFrame(), []any{"category", dataframe.ByRegex("^reg")}, aggregations)
+	if !slices.Equal(result.ColumnNames(), byName.ColumnNames()) {
+		t.Errorf("Expected columns %v, got %v", byName.ColumnNames(), result.ColumnNames())
+	}
+}
@@ -0,0 +1,118 @@
+package aggregate
+
+import (
+	"fmt"
+	"teddy/dataframe"
+	"teddy/dataframe/series"
+)
+
+// PivotTable reshapes a DataFrame from long to wide format, combining duplicate cells with the aggregator.
+//
+// Each unique combination of the index columns becomes a row and each unique value
+// of the columns column becomes a new column holding the aggregated values.
+//
+// Options:
+//   - fill_value: any (default: nil) The value for cells without a matching row.
+//   - margins: bool (default: false) If true, adds a total column and a total row.
+//   - margins_name: string (default: "All") The name of the total column and row. It must not be
+//     the name of an index column or a pivoted column label.
+func PivotTable(df *dataframe.DataFrame, index []string, columns string, values string, aggregator Aggregator, options ...dataframe.OptionsMap) (*dataframe.DataFrame, error) {
+	if err := df.Err(); err != nil {
+		return nil, err
+	}
+
+	var option dataframe.OptionsMap
+	if len(options) > 0 {
+		option = options[0]
+	}
+	fillValue := option.Get("fill_value", nil)
+	margins, ok := option.Get("margins", false).(bool)
+	if !ok {
+		return nil, fmt.Errorf("The margins option must be a bool, got %T", option.Get("margins", false))
+	}
+	marginsName, ok := option.Get("margins_name", "All").(string)
+	if !ok {
+		return nil, fmt.Errorf("The margins_name option must be a string, got %T", option.Get("margins_name", "All"))
+	}
+
+	for _, col := range append(append([]string{}, index...), columns, values) {
+		if !df.HasColumn(col) {
+			return nil, fmt.Errorf("%w: \"%s\"", dataframe.ErrColumnNotFound, col)
+		}
+	}
+
+	// Aggregate the duplicates so every cell has a single value
+	by := append(append([]string{}, index...), columns)
+	grouped := GroupBy(df, by, map[string]Aggregator{values: aggregator})
+
+	result, err := grouped.Pivot(index, columns, values, dataframe.OptionsMap{"fill_value": fillValue})
+	if err != nil {
+		return nil, err
+	}
+
+	if !margins {
+		return result, nil
+	}
+	if result.HasColumn(marginsName) {
+		return nil, fmt.Errorf("The margins name \"%s\" is already a column of the pivot table, set margins_name to another name", marginsName)
+	}
+
+	// Total column: aggregate each row over all columns
+	rowTotals := totalsByKey(GroupBy(df, index, map[string]Aggregator{values: aggregator}), index, values)
+	indexSeries := make([]series.SeriesInterface, len(index))
+	for i, col := range index {
+		indexSeries[i] = result.GetSeries(col)
+	}
+	totalValues := make([]any, result.Height())
+	for i := range totalValues {
+		totalValues[i] = rowTotals[groupKey(indexSeries, i)]
+	}
+	result = result.AddSeries(series.NewSeries(marginsName, totalValues))
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+
+	// Total row: aggregate each column over all rows
+	columnTotals := totalsByKey(GroupBy(df, []string{columns}, map[string]Aggregator{values: aggregator}), []string{columns}, values)
+	totalRow := dataframe.NewDataFrame()
+	for i, name := range result.ColumnNames() {
+		var value any
+		switch {
+		case i == 0 && len(index) > 0:
+			value = marginsName
+		case i < len(index):
+			value = nil
+		case name == marginsName:
+			value = Apply(df.GetSeries(values), aggregator)
+		default:
+			value = columnTotals[name+"|"]
+		}
+		totalRow = totalRow.AddSeries(series.NewSeries(name, []any{value}))
+	}
+
+	return dataframe.Concat(result, totalRow)
+}
+
+// totalsByKey maps the group key of each row in a grouped DataFrame to its aggregated value
+func totalsByKey(grouped *dataframe.DataFrame, by []string, values string) map[string]any {
+	keySeries := make([]series.SeriesInterface, len(by))
+	for i, col := range by {
+		keySeries[i] = grouped.GetSeries(col)
+	}
+
+	totals := make(map[string]any)
+	valueSeries := grouped.GetSeries(values)
+	for i := 0; i < grouped.Height(); i++ {
+		totals[groupKey(keySeries, i)] = valueSeries.Get(i)
+	}
+	return totals
+}
+
+// groupKey builds the same composite key as GroupBy from the values at a row
+func groupKey(keySeries []series.SeriesInterface, row int) string {
+	key := ""
+	for _, s := range keySeries {
+		key += fmt.Sprintf("%v|", s.Get(row))
+	}
+	return key
+}
@@ -0,0 +1,92 @@
+package aggregate_test
+
+import (
+	"slices"
+	"teddy/dataframe"
+	"teddy/dataframe/aggregate"
+	"testing"
+)
+
+func TestPivotTable(t *testing.T) {
+	// Tests pivoting with duplicate cells combined by an aggregator
+	df := createTestDataFrame()
+
+	result, err := aggregate.PivotTable(df, []string{"category"}, "region", "sales", aggregate.Sum(),
+		dataframe.OptionsMap{"fill_value": 0})
+	if err != nil {
+		t.Fatalf("Error creating pivot table: %v", err)
+	}
+
+	expected := []string{"category", "East", "West"}
+	if !slices.Equal(result.ColumnNames(), expected) {
+		t.Errorf("Expected columns %v, got %v", expected, result.ColumnNames())
+	}
+	if result.Height() != 3 {
+		t.Errorf("Expected 3 rows, got %d", result.Height())
+	}
+
+	// Category A has two East sales (100 + 120) and one West sale (150)
+	east := result.GetSeries("East")
+	west := result.GetSeries("West")
+	if east.Get(0) != 220 || west.Get(0) != 150 {
+		t.Errorf("Expected A to have East 220 and West 150, got %v and %v", east.Get(0), west.Get(0))
+	}
+}
+
+func TestPivotTableMargins(t *testing.T) {
+	// Tests the total row and column of a pivot table
+	df := createTestDataFrame()
+
+	result, err := aggregate.PivotTable(df, []string{"category"}, "region", "sales", aggregate.Sum(),
+		dataframe.OptionsMap{"margins": true, "fill_value": 0})
+	if err != nil {
+		t.Fatalf("Error creating pivot table: %v", err)
+	}
+
+	if result.Height() != 4 || result.Width() != 4 {
+		t.Fatalf("Expected 4 rows and 4 columns, got %d rows and %d columns", result.Height(), result.Width())
+	}
+
+	// Row totals
+	all := result.GetSeries("All")
+	if all.Get(0) != 370 || all.Get(1) != 450 || all.Get(2) != 480 {
+		t.Errorf("Expected row totals [370 450 480], got %v", all.Values())
+	}
+
+	// Column totals and grand total
+	if result.GetSeries("category").Get(3) != "All" {
+		t.Errorf("Expected the last row to be the total row, got %v", result.GetSeries("category").Get(3))
+	}
+	if result.GetSeries("East").Get(3) != 600 || result.GetSeries("West").Get(3) != 700 || all.Get(3) != 1300 {
+		t.Errorf("Expected totals [600 700 1300], got [%v %v %v]",
+			result.GetSeries("East").Get(3), result.GetSeries("West").Get(3), all.Get(3))
+	}
+}
+
+func TestPivotTableMarginsWithoutIndex(t *testing.T) {
+	// Tests that the total row keeps the column totals when there are no index columns
+	result, err := aggregate.PivotTable(createTestDataFrame(), []string{}, "region", "sales", aggregate.Sum(),
+		dataframe.OptionsMap{"margins": true})
+	if err != nil {
+		t.Fatalf("Error creating pivot table: %v", err)
+	}
+
+	last := result.Height() - 1
+	if result.GetSeries("East").Get(last) != 600 || result.GetSeries("West").Get(last) != 700 {
+		t.Errorf("Expected column totals [600 700], got %v", []any{result.GetSeries("East").Values(), result.GetSeries("West").Values()})
+	}
+}
+
+func TestPivotTableMarginsErrors(t *testing.T) {
+	// Tests that a margins name used by a column and options of the wrong type are errors
+	for _, options := range []dataframe.OptionsMap{
+		{"margins": true, "margins_name": "East"},
+		{"margins": true, "margins_name": "category"},
+		{"margins": "yes"},
+		{"margins": true, "margins_name": 1},
+	} {
+		if _, err := aggregate.PivotTable(createTestDataFrame(), []string{"category"}, "region", "sales", aggregate.Sum(), options); err == nil {
+			t.Errorf("Options %v: expected an error", options)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package dataframe
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"teddy/dataframe/series"
+)
+
+// ColumnSelector picks columns of a DataFrame by a rule instead of by name.
+//
+// Selectors can be used wherever columns are selected with GetColumnNames,
+// such as Select, DropColumn and ReorderColumns, mixed with names and indexes.
+type ColumnSelector func(df *DataFrame) ([]string, error)
+
+// All selects all columns
+func All() ColumnSelector {
+	return func(df *DataFrame) ([]string, error) {
+		return df.ColumnNames(), nil
+	}
+}
+
+// ByRegex selects the columns with names matching the regular expression
+func ByRegex(pattern string) ColumnSelector {
+	return func(df *DataFrame) ([]string, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return slices.DeleteFunc(df.ColumnNames(), func(name string) bool { return !re.MatchString(name) }), nil
+	}
+}
+
+// ByType selects the columns with one of the types, named as in a Schema:
+// "int", "float64", "string", "bool", "time" or "any"
+func ByType(types ...string) ColumnSelector {
+	return func(df *DataFrame) ([]string, error) {
+		names := []string{}
+		for _, typ := range types {
+			if _, ok := schemaTypes[typ]; !ok {
+				return nil, fmt.Errorf("Unknown type \"%s\"", typ)
+			}
+		}
+		for _, s := range df.series {
+			if slices.Contains(types, seriesTypeName(s)) {
+				names = append(names, s.Name())
+			}
+		}
+		return names, nil
+	}
+}
+
+// Exclude selects all columns except the selected ones
+func Exclude(selectedColumns ...any) ColumnSelector {
+	return func(df *DataFrame) ([]string, error) {
+		excluded, err := df.GetColumnNames(selectedColumns...)
+		if err != nil {
+			return nil, err
+		}
+		return slices.DeleteFunc(df.ColumnNames(), func(name string) bool { return slices.Contains(excluded, name) }), nil
+	}
+}
+
+// InsertSeries adds the Series as the column at the position, moving the following columns right.
+//
+// Sets an error if the position is out of range, or the Series doesn't match the number of rows.
+func (df *DataFrame) InsertSeries(at int, s series.SeriesInterface) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+	if at < 0 || at > df.Width() {
+		return df.withError(fmt.Errorf("Position %d is out of range for %d columns", at, df.Width()))
+	}
+	if df.Width() != 0 && s.Len() != df.Height() {
+		return df.withError(fmt.Errorf("%w: Series \"%s\" has %d values, the DataFrame has %d rows", ErrLengthMismatch, s.Name(), s.Len(), df.Height()))
+	}
+
+	result := df.target()
+	result.series = slices.Insert(result.series, at, s)
+	return result
+}
+
+// ReorderColumns moves the selected columns to the start, in the order they are selected.
+// The other columns follow in their current order.
+func (df *DataFrame) ReorderColumns(selectedColumns ...any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	columns, err := df.GetColumnNames(selectedColumns...)
+	if err != nil {
+		return df.withError(err)
+	}
+	for _, name := range df.ColumnNames() {
+		if !slices.Contains(columns, name) {
+			columns = append(columns, name)
+		}
+	}
+
+	// Resolve the Series first, since in place mode the result is df itself
+	reordered := make([]series.SeriesInterface, len(columns))
+	for i, name := range columns {
+		reordered[i] = df.GetSeries(name)
+	}
+
+	result := df.target()
+	result.series = reordered
+	return result
+}
+
+// RenameWith renames many columns at once.
+//
+// The renamer is a func(string) string called with each column name, or a
+// map[string]string from old to new names, where the old names must exist.
+// Sets an error if two columns would get the same name.
+func (df *DataFrame) RenameWith(renamer any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	var rename func(string) string
+	switch r := renamer.(type) {
+	case func(string) string:
+		rename = r
+	case map[string]string:
+		for old := range r {
+			if !df.HasColumn(old) {
+				return df.withError(columnNotFound(old))
+			}
+		}
+		rename = func(name string) string {
+			if newName, ok := r[name]; ok {
+				return newName
+			}
+			return name
+		}
+	default:
+		return df.withError(fmt.Errorf("Unsupported renamer of type %T", renamer))
+	}
+
+	names := make([]string, df.Width())
+	for i, s := range df.series {
+		names[i] = rename(s.Name())
+		if slices.Contains(names[:i], names[i]) {
+			return df.withError(fmt.Errorf("Duplicate column name \"%s\" after renaming", names[i]))
+		}
+	}
+
+	result := df.target()
+	for i, s := range result.series {
+		if names[i] != s.Name() {
+			result.series[i] = s.Rename(names[i])
+		}
+	}
+	return result
+}
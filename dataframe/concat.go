@@ -0,0 +1,291 @@
+package dataframe
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"teddy/dataframe/series"
+	"time"
+)
+
+// Concat stacks DataFrames vertically, aligning columns by name.
+//
+// Columns that are missing from a DataFrame are filled with nulls.
+// Columns with different types are promoted to a common type (int + float -> float).
+// Columns that can't be promoted fall back to a GenericSeries.
+// Columns with the same type in every DataFrame link their data as chunks instead
+// of copying it.
+func Concat(frames ...*DataFrame) (*DataFrame, error) {
+	return ConcatWith(nil, frames...)
+}
+
+// ConcatWith stacks DataFrames vertically as Concat does, with options.
+//
+// Options:
+//   - join: string (default: "outer") "outer" keeps the union of all columns,
+//     "inner" keeps only the columns present in every DataFrame.
+func ConcatWith(options OptionsMap, frames ...*DataFrame) (*DataFrame, error) {
+	for _, frame := range frames {
+		if frame.err != nil {
+			return nil, frame.err
+		}
+	}
+
+	optionsClean := standardizeOptions(options)
+	join, ok := optionsClean.getOption("join", "outer").(string)
+	if !ok {
+		return nil, fmt.Errorf("The join option must be a string, got %T", optionsClean["join"])
+	}
+
+	// Collect the column names in order of first appearance
+	columnNames := []string{}
+	for _, frame := range frames {
+		for _, name := range frame.ColumnNames() {
+			if !slices.Contains(columnNames, name) {
+				columnNames = append(columnNames, name)
+			}
+		}
+	}
+
+	switch join {
+	case "outer":
+	case "inner":
+		columnNames = slices.DeleteFunc(columnNames, func(name string) bool {
+			for _, frame := range frames {
+				if !frame.HasColumn(name) {
+					return true
+				}
+			}
+			return false
+		})
+	default:
+		return nil, errors.New("Unknown join mode: " + join)
+	}
+
+	heights := make([]int, len(frames))
+	for i, frame := range frames {
+		heights[i] = frame.Height()
+	}
+
+	result := NewDataFrame()
+	for _, name := range columnNames {
+		// A nil part means the column is missing from that DataFrame
+		parts := make([]series.SeriesInterface, len(frames))
+		for i, frame := range frames {
+			parts[i] = frame.GetSeries(name)
+		}
+
+		if linkable(parts) {
+			result.series = append(result.series, series.Link(name, parts...))
+		} else {
+			result.series = append(result.series, concatSeries(name, parts, heights))
+		}
+	}
+
+	return result, nil
+}
+
+// HConcat places DataFrames side by side.
+//
+// All DataFrames must have the same height. The resulting DataFrame shares the
+// underlying data of the inputs instead of copying it.
+// Column names that appear in more than one DataFrame are suffixed with the
+// suffix of the DataFrame they come from.
+func HConcat(frames ...*DataFrame) (*DataFrame, error) {
+	return HConcatWith(nil, frames...)
+}
+
+// HConcatWith places DataFrames side by side as HConcat does, with options.
+//
+// Options:
+//   - suffixes: []string (default: "_0", "_1", ...) One suffix per DataFrame.
+func HConcatWith(options OptionsMap, frames ...*DataFrame) (*DataFrame, error) {
+	for _, frame := range frames {
+		if frame.err != nil {
+			return nil, frame.err
+		}
+	}
+
+	optionsClean := standardizeOptions(options)
+
+	defaultSuffixes := make([]string, len(frames))
+	for i := range frames {
+		defaultSuffixes[i] = fmt.Sprintf("_%d", i)
+	}
+	suffixes, ok := optionsClean.getOption("suffixes", defaultSuffixes).([]string)
+	if !ok {
+		return nil, fmt.Errorf("The suffixes option must be a []string, got %T", optionsClean["suffixes"])
+	}
+	if len(suffixes) != len(frames) {
+		return nil, fmt.Errorf("Expected %d suffixes, got %d", len(frames), len(suffixes))
+	}
+
+	// Check that all DataFrames have the same height
+	for i, frame := range frames {
+		if frame.Height() != frames[0].Height() {
+			return nil, fmt.Errorf("%w: DataFrame %d has %d rows, expected %d", ErrLengthMismatch, i, frame.Height(), frames[0].Height())
+		}
+	}
+
+	// Count how many DataFrames each column name appears in
+	nameCounts := make(map[string]int)
+	for _, frame := range frames {
+		for _, name := range frame.ColumnNames() {
+			nameCounts[name]++
+		}
+	}
+
+	result := NewDataFrame()
+	for i, frame := range frames {
+		for _, s := range frame.series {
+			name := s.Name()
+			if nameCounts[name] > 1 {
+				name += suffixes[i]
+			}
+			if result.HasColumn(name) {
+				return nil, errors.New("Duplicate column name: " + name)
+			}
+
+			// Renaming shares the values without changing the input
+			result.series = append(result.series, s.Rename(name))
+		}
+	}
+
+	return result, nil
+}
+
+// concatSeries joins the parts of a column into a single Series of the promoted type
+func concatSeries(name string, parts []series.SeriesInterface, heights []int) series.SeriesInterface {
+	return concatSeriesOfType(name, promoteType(parts), parts, heights)
+}
+
+// linkable returns true if all parts are typed Series of the same type, which can be
+// linked as chunks without copying
+func linkable(parts []series.SeriesInterface) bool {
+	for _, part := range parts {
+		if part == nil || part.Type() == nil || part.Type() != parts[0].Type() {
+			return false
+		}
+		if _, ok := part.(*series.GenericSeries); ok {
+			return false
+		}
+	}
+	return len(parts) > 0
+}
+
+// appendRows appends the rows of tail, which must be linkable with s.
+// The last chunk is copied while it has fewer than series.DefaultChunkSize rows,
+// then a new chunk is started, so the earlier chunks are never copied.
+func appendRows(s, tail series.SeriesInterface) series.SeriesInterface {
+	chunks := []series.SeriesInterface{s}
+	if chunked, ok := s.(*series.ChunkedSeries); ok {
+		chunks = chunked.Chunks()
+	}
+
+	last := len(chunks) - 1
+	if len(chunks) == 0 || chunks[last].Len()+tail.Len() > series.DefaultChunkSize {
+		return series.Link(s.Name(), append(chunks, tail)...)
+	}
+	parts := []series.SeriesInterface{chunks[last], tail}
+	chunks[last] = concatSeries(s.Name(), parts, []int{chunks[last].Len(), tail.Len()})
+	return series.Link(s.Name(), chunks...)
+}
+
+// newSeriesOfType creates a Series of the given type from values, treating nil as null
+func newSeriesOfType(name string, typ reflect.Type, values []any) series.SeriesInterface {
+	return concatSeriesOfType(name, typ, []series.SeriesInterface{series.NewGenericSeries(name, values)}, []int{len(values)})
+}
+
+// concatSeriesOfType joins the parts of a column into a single Series of the given type.
+// A nil type creates a GenericSeries.
+func concatSeriesOfType(name string, typ reflect.Type, parts []series.SeriesInterface, heights []int) series.SeriesInterface {
+	switch typ {
+	case intType:
+		values, nulls := concatValues(parts, heights, func(v any) int { return v.(int) })
+		return series.NewIntSeriesWithNulls(name, values, nulls)
+	case float64Type:
+		values, nulls := concatValues(parts, heights, func(v any) float64 {
+			if i, ok := v.(int); ok {
+				return float64(i)
+			}
+			return v.(float64)
+		})
+		return series.NewFloat64SeriesWithNulls(name, values, nulls)
+	case stringType:
+		values, nulls := concatValues(parts, heights, func(v any) string { return v.(string) })
+		return series.NewStringSeriesWithNulls(name, values, nulls)
+	case boolType:
+		values, nulls := concatValues(parts, heights, func(v any) bool { return v.(bool) })
+		return series.NewBoolSeriesWithNulls(name, values, nulls)
+	case timeType:
+		values, nulls := concatValues(parts, heights, func(v any) time.Time { return v.(time.Time) })
+		return series.NewTimeSeriesWithNulls(name, values, nulls)
+	}
+
+	values, _ := concatValues(parts, heights, func(v any) any { return v })
+	return series.NewGenericSeries(name, values)
+}
+
+var (
+	intType     = reflect.TypeOf(0)
+	float64Type = reflect.TypeOf(0.0)
+	stringType  = reflect.TypeOf("")
+	boolType    = reflect.TypeOf(true)
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// promoteType returns the type all parts can be converted to, or nil if there is none
+func promoteType(parts []series.SeriesInterface) reflect.Type {
+	var typ reflect.Type
+	for _, part := range parts {
+		if part == nil {
+			continue
+		}
+
+		partType := part.Type()
+		switch {
+		case partType != intType && partType != float64Type && partType != stringType && partType != boolType && partType != timeType:
+			return nil
+		case typ == nil || typ == partType:
+			typ = partType
+		case (typ == intType && partType == float64Type) || (typ == float64Type && partType == intType):
+			typ = float64Type
+		default:
+			return nil
+		}
+	}
+	return typ
+}
+
+// concatValues appends the values of each part, converting them with convert.
+// Missing parts and null values are recorded in the returned mask, which is nil if there are no nulls.
+func concatValues[T any](parts []series.SeriesInterface, heights []int, convert func(any) T) ([]T, []bool) {
+	total := 0
+	for _, height := range heights {
+		total += height
+	}
+
+	values := make([]T, 0, total)
+	nulls := make([]bool, 0, total)
+	hasNulls := false
+
+	var zero T
+	for i, part := range parts {
+		for j := 0; j < heights[i]; j++ {
+			if part == nil || part.IsNull(j) {
+				values = append(values, zero)
+				nulls = append(nulls, true)
+				hasNulls = true
+				continue
+			}
+			values = append(values, convert(part.Get(j)))
+			nulls = append(nulls, false)
+		}
+	}
+
+	if !hasNulls {
+		return values, nil
+	}
+	return values, nulls
+}
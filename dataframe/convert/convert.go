@@ -212,9 +212,12 @@ func convertToTime(value any) (time.Time, error) {
 		return v, nil
 	case string:
 		return parseTime(v)
-	case int, int64:
+	case int:
+		// Interpret as Unix timestamp
+		return time.Unix(int64(v), 0), nil
+	case int64:
 		// Interpret as Unix timestamp
-		return time.Unix(int64(v.(int64)), 0), nil
+		return time.Unix(v, 0), nil
 	}
 	errorMessage := fmt.Sprintf("error: could not convert value of type %T to time. The Value is %v", value, value)
 	return time.Time{}, errors.New(errorMessage)
@@ -1,18 +1,24 @@
 package dataframe
 
 import (
-	"errors"
 	"fmt"
 	"slices"
+	"teddy/dataframe/internal/parallel"
 	"teddy/dataframe/series"
 )
 
 type DataFrame struct {
-	series []series.SeriesInterface
+	series  []series.SeriesInterface
+	err     error
+	inPlace bool
+	// parallelism is the number of goroutines for row operations, 0 for the default
+	parallelism int
+	// index labels the rows, nil if the DataFrame has no index
+	index *rowIndex
 }
 
 func NewDataFrame(series ...series.SeriesInterface) *DataFrame {
-	return &DataFrame{series}
+	return &DataFrame{series: series}
 }
 
 func (df *DataFrame) allColumnsExist(columnNames []string) bool {
@@ -46,24 +52,62 @@ func (df *DataFrame) GetSeries(columnName string, options ...OptionsMap) series.
 	return nil
 }
 
+// SetInPlace sets whether operations modify the DataFrame instead of returning a new one.
+//
+// By default operations return a new DataFrame that shares the unchanged Series with
+// the original, so neither DataFrame is affected by changes to the other.
+// In place mode changes the DataFrame itself instead of creating a new one. This only
+// avoids copying its list of Series, not the data: Series are never modified, since
+// other DataFrames and callers of GetSeries may share them, so DropRows, AsType and
+// the other operations that change values copy the columns they change exactly as
+// they do by default.
+// Operations still return the DataFrame so they can be chained.
+func (df *DataFrame) SetInPlace(inPlace bool) *DataFrame {
+	df.inPlace = inPlace
+	return df
+}
+
+// target returns the DataFrame an operation should write its result to.
+//
+// This is the DataFrame itself in place mode, otherwise a new DataFrame sharing its Series.
+func (df *DataFrame) target() *DataFrame {
+	if df.inPlace {
+		return df
+	}
+	return &DataFrame{series: slices.Clone(df.series), err: df.err, index: df.index, parallelism: df.parallelism}
+}
+
+// setSeries replaces the column with the same name as the Series, or adds it if it doesn't exist.
+// A replaced column is moved to the end.
+func (df *DataFrame) setSeries(s series.SeriesInterface) {
+	if index, ok := df.GetColumnIndex(s.Name()); ok {
+		df.series = slices.Delete(df.series, index, index+1)
+	}
+	df.series = append(df.series, s)
+}
+
 func (df *DataFrame) Rename(oldColumnName, newColumnName string) *DataFrame {
-	for i, series := range df.series {
+	result := df.target()
+	for i, series := range result.series {
 		if series.Name() == oldColumnName {
-			df.series[i] = series.Rename(newColumnName)
+			result.series[i] = series.Rename(newColumnName)
 		}
 	}
-	return df
+	return result
 }
 
 // ApplyIndex applies a function to each row of the specified columns.
 //
 // The function takes variable arguments of any type and returns a single value of any type.
 func (df *DataFrame) ApplyIndex(newColumnName string, f func(...any) any, cols ...any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
 	// Get the column names
 	columns, err := df.GetColumnNames(cols...)
 	if err != nil {
-		fmt.Println(err)
-		return df
+		return df.withError(err)
 	}
 
 	// Get the column indexes
@@ -73,67 +117,70 @@ func (df *DataFrame) ApplyIndex(newColumnName string, f func(...any) any, cols .
 		columnIndexs = append(columnIndexs, columnIndex)
 	}
 
-	// Create the new column
+	// Create the new column, each range of rows writing its own values
 	newValues := make([]any, df.Height())
-	for i := 0; i < df.Height(); i++ {
-		// List of Values to be used
-		values := make([]any, len(columnIndexs))
-		for j, columnIndex := range columnIndexs {
-			values[j] = df.series[columnIndex].Get(i)
-		}
-
-		newValues[i] = f(values...)
-	}
+	parallel.Run(parallel.Ranges(df.Height(), df.Parallelism()), func(_, start, end int) {
+		for i := start; i < end; i++ {
+			// List of Values to be used
+			values := make([]any, len(columnIndexs))
+			for j, columnIndex := range columnIndexs {
+				values[j] = df.series[columnIndex].Get(i)
+			}
 
-	// If the column already exists, drop it
-	if df.HasColumn(newColumnName) {
-		df = df.DropColumn(newColumnName)
-	}
+			newValues[i] = f(values...)
+		}
+	})
 
-	// Add the new column to the DataFrame
-	df.series = append(df.series, series.NewSeries(newColumnName, newValues))
+	// Add the new column to the DataFrame, replacing it if it already exists
+	result := df.target()
+	result.setSeries(series.NewSeries(newColumnName, newValues))
 
-	return df
+	return result
 }
 
 // ApplyMap applies a function to each row as a map of column name to value.
 //
 // The function takes a map of column names to values and returns a single value of any type.
 func (df *DataFrame) ApplyMap(newColumnName string, f func(map[string]any) any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
 	columns := df.ColumnNames()
 
-	// Create the new column
+	// Create the new column, each range of rows writing its own values
 	newValues := make([]any, df.Height())
-	for i := 0; i < df.Height(); i++ {
-		// Create map of column name to value
-		rowMap := make(map[string]any)
-		for j, series := range df.series {
-			rowMap[columns[j]] = series.Get(i)
-		}
-
-		newValues[i] = f(rowMap)
-	}
+	parallel.Run(parallel.Ranges(df.Height(), df.Parallelism()), func(_, start, end int) {
+		for i := start; i < end; i++ {
+			// Create map of column name to value
+			rowMap := make(map[string]any)
+			for j, series := range df.series {
+				rowMap[columns[j]] = series.Get(i)
+			}
 
-	// If the column already exists, drop it
-	if df.HasColumn(newColumnName) {
-		df = df.DropColumn(newColumnName)
-	}
+			newValues[i] = f(rowMap)
+		}
+	})
 
-	// Add the new column to the DataFrame
-	df.series = append(df.series, series.NewSeries(newColumnName, newValues))
+	// Add the new column to the DataFrame, replacing it if it already exists
+	result := df.target()
+	result.setSeries(series.NewSeries(newColumnName, newValues))
 
-	return df
+	return result
 }
 
 // ApplySeries applies a function to entire columns.
 //
 // The function takes variable arguments of slices of any and returns a slice of any.
 func (df *DataFrame) ApplySeries(newColumnName string, f func(...[]any) []any, cols ...any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
 	// Get the column names
 	columns, err := df.GetColumnNames(cols...)
 	if err != nil {
-		fmt.Println(err)
-		return df
+		return df.withError(err)
 	}
 
 	// Get the column values
@@ -145,27 +192,29 @@ func (df *DataFrame) ApplySeries(newColumnName string, f func(...[]any) []any, c
 
 	// Apply the function to get new values
 	newValues := f(columnValues...)
-
-	// If the column already exists, drop it
-	if df.HasColumn(newColumnName) {
-		df = df.DropColumn(newColumnName)
+	if len(newValues) != df.Height() {
+		return df.withError(fmt.Errorf("%w: function returned %d values for %d rows", ErrLengthMismatch, len(newValues), df.Height()))
 	}
 
-	// Add the new column to the DataFrame
-	df.series = append(df.series, series.NewSeries(newColumnName, newValues))
+	// Add the new column to the DataFrame, replacing it if it already exists
+	result := df.target()
+	result.setSeries(series.NewSeries(newColumnName, newValues))
 
-	return df
+	return result
 }
 
 // FilterIndex filters rows by applying a function to the values of specified columns.
 //
 // The function takes variable arguments of any type and returns a boolean.
 func (df *DataFrame) FilterIndex(f func(...any) bool, cols ...any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
 	// Get the column names
 	columns, err := df.GetColumnNames(cols...)
 	if err != nil {
-		fmt.Println(err)
-		return df
+		return df.withError(err)
 	}
 
 	// Get the column indexes
@@ -176,8 +225,7 @@ func (df *DataFrame) FilterIndex(f func(...any) bool, cols ...any) *DataFrame {
 	}
 
 	// Apply the filter function to each row
-	dropIndexes := make([]int, 0)
-	for i := 0; i < df.Height(); i++ {
+	dropIndexes := df.rowsWhere(func(i int) bool {
 		// List of values to be used
 		values := make([]any, len(columnIndexes))
 		for j, columnIndex := range columnIndexes {
@@ -185,10 +233,8 @@ func (df *DataFrame) FilterIndex(f func(...any) bool, cols ...any) *DataFrame {
 		}
 
 		// If filter returns false, mark row for removal
-		if !f(values...) {
-			dropIndexes = append(dropIndexes, i)
-		}
-	}
+		return !f(values...)
+	})
 
 	// Drop the rows that don't pass the filter
 	df = df.DropRows(dropIndexes...)
@@ -200,11 +246,14 @@ func (df *DataFrame) FilterIndex(f func(...any) bool, cols ...any) *DataFrame {
 //
 // The function takes a map of column names to values and returns a boolean.
 func (df *DataFrame) FilterMap(f func(map[string]any) bool) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
 	columns := df.ColumnNames()
 
 	// Apply the filter function to each row
-	dropIndexes := make([]int, 0)
-	for i := 0; i < df.Height(); i++ {
+	dropIndexes := df.rowsWhere(func(i int) bool {
 		// Create map of column name to value
 		rowMap := make(map[string]any)
 		for j, series := range df.series {
@@ -212,10 +261,8 @@ func (df *DataFrame) FilterMap(f func(map[string]any) bool) *DataFrame {
 		}
 
 		// If filter returns false, mark row for removal
-		if !f(rowMap) {
-			dropIndexes = append(dropIndexes, i)
-		}
-	}
+		return !f(rowMap)
+	})
 
 	// Drop the rows that don't pass the filter
 	df = df.DropRows(dropIndexes...)
@@ -281,21 +328,24 @@ func (df *DataFrame) Shape() (int, int) {
 }
 
 func (df *DataFrame) DropRow(index int) *DataFrame {
-	for i, series := range df.series {
-		df.series[i] = series.DropRow(index)
+	if df.err != nil {
+		return df
 	}
-	return df
+
+	return df.DropRows(index)
 }
 
 func (df *DataFrame) DropRows(indexes ...int) *DataFrame {
-	// Sort the indexes in reverse order
-	slices.Sort(indexes)
-	slices.Reverse(indexes)
+	if df.err != nil {
+		return df
+	}
 
-	for i := range indexes {
-		df.DropRow(indexes[i])
+	result := df.target()
+	for i, series := range result.series {
+		result.series[i] = series.DropRows(indexes...)
 	}
-	return df
+	result.index = result.index.dropRows(indexes)
+	return result
 }
 
 func (df *DataFrame) DropRowsBySeries(series series.SeriesInterface) *DataFrame {
@@ -312,144 +362,125 @@ func (df *DataFrame) DropRowsBySeries(series series.SeriesInterface) *DataFrame
 }
 
 func (df *DataFrame) DropColumn(selectedColumn ...any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
 	if len(df.series) == 0 {
-		return &DataFrame{}
+		return df.target()
 	}
 
-	// Check if all values are of the same type
 	columns, err := df.GetColumnNames(selectedColumn...)
 	if err != nil {
-		fmt.Println(err)
-		return &DataFrame{}
+		return df.withError(err)
 	}
 
+	result := df.target()
 	for _, columnName := range columns {
-		for index, series := range df.series {
+		for index, series := range result.series {
 			if series.Name() == columnName {
-				df.series = slices.Delete(df.series, index, index+1)
+				result.series = slices.Delete(result.series, index, index+1)
 				break
 			}
 		}
 	}
 
-	return df
+	return result
 }
 
 func (df *DataFrame) AsType(columnName string, newType string) *DataFrame {
-	for i, series := range df.series {
-		if series.Name() == columnName {
-			df.series[i] = series.AsType(newType)
-		}
+	if df.err != nil {
+		return df
 	}
-	return df
+
+	i, ok := df.GetColumnIndex(columnName)
+	if !ok {
+		return df.withError(columnNotFound(columnName))
+	}
+
+	newSeries, err := df.series[i].AsType(newType)
+	if err != nil {
+		return df.withError(err)
+	}
+	result := df.target()
+	result.series[i] = newSeries
+	return result
 }
 
 func (df *DataFrame) AddSeries(series series.SeriesInterface) *DataFrame {
-	// If the DataFrame is empty, add the Series
-	if df.Width() == 0 {
-		df.series = append(df.series, series)
+	if df.err != nil {
 		return df
 	}
 
-	// Check if the Series is the same length as the DataFrame
-	if series.Len() != df.Height() {
-		fmt.Println("Series must be the same length as the DataFrame")
-		return df
+	// Check if the Series is the same length as the DataFrame, unless the DataFrame is empty
+	if df.Width() != 0 && series.Len() != df.Height() {
+		return df.withError(fmt.Errorf("%w: Series \"%s\" has %d values, the DataFrame has %d rows", ErrLengthMismatch, series.Name(), series.Len(), df.Height()))
 	}
 
-	df.series = append(df.series, series)
-	return df
+	result := df.target()
+	result.series = append(result.series, series)
+	return result
 }
 
 func (df *DataFrame) AddRow(row []any) *DataFrame {
-	if df.Width() == 0 {
+	if df.err != nil {
 		return df
 	}
 
 	if len(row) != df.Width() {
-		fmt.Println("Row must be the same length as the DataFrame")
-		return df
+		return df.withError(fmt.Errorf("%w: row has %d values, the DataFrame has %d columns", ErrLengthMismatch, len(row), df.Width()))
 	}
 
-	// Create new rows for each series
+	// Append the value to each series, promoting the type if needed (int + float -> float)
+	result := df.target()
 	for i, value := range row {
-		seriess := df.series[i]
-
-		// For typed series, we need to handle type conversion
-		switch s := seriess.(type) {
-		case *series.IntSeries:
-			if intVal, ok := value.(int); ok {
-				newValues := append(s.Values(), intVal)
-				intValues, _ := series.ToIntSlice(newValues)
-				df.series[i] = series.NewIntSeries(s.Name(), intValues)
-			} else {
-				// Convert to int or fall back to generic
-				genSeries := s.ToGenericSeries()
-				genValues := append(genSeries.Values(), value)
-				df.series[i] = series.NewGenericSeries(genSeries.Name(), genValues)
-			}
-		case *series.Float64Series:
-			if floatVal, ok := value.(float64); ok {
-				newValues := append(s.Values(), floatVal)
-				floatValues, _ := series.ToFloat64Slice(newValues)
-				df.series[i] = series.NewFloat64Series(s.Name(), floatValues)
-			} else {
-				// Convert to float64 or fall back to generic
-				genSeries := s.ToGenericSeries()
-				genValues := append(genSeries.Values(), value)
-				df.series[i] = series.NewGenericSeries(genSeries.Name(), genValues)
-			}
-		case *series.StringSeries:
-			if strVal, ok := value.(string); ok {
-				newValues := append(s.Values(), strVal)
-				stringValue := series.ToStringSlice(newValues)
-				df.series[i] = series.NewStringSeries(s.Name(), stringValue)
-			} else {
-				// Convert to string or fall back to generic
-				genSeries := s.ToGenericSeries()
-				genValues := append(genSeries.Values(), value)
-				df.series[i] = series.NewGenericSeries(genSeries.Name(), genValues)
-			}
-		case *series.BoolSeries:
-			if boolVal, ok := value.(bool); ok {
-				newValues := append(s.Values(), boolVal)
-				boolValues, _ := series.ToBoolSlice(newValues)
-				df.series[i] = series.NewBoolSeries(s.Name(), boolValues)
-			} else {
-				// Convert to bool or fall back to generic
-				genSeries := s.ToGenericSeries()
-				genValues := append(genSeries.Values(), value)
-				df.series[i] = series.NewGenericSeries(genSeries.Name(), genValues)
-			}
-		case *series.GenericSeries:
-			newValues := append(s.Values(), value)
-			df.series[i] = series.NewGenericSeries(s.Name(), newValues)
+		s := result.series[i]
+
+		// A nil value is appended as a null
+		var valueSeries series.SeriesInterface
+		if value != nil {
+			valueSeries = series.NewSeries(s.Name(), []any{value})
+		} else if s.Type() != nil {
+			valueSeries = newSeriesOfType(s.Name(), s.Type(), []any{nil})
+		}
+
+		// Values of the same type are appended to the chunks of the column
+		parts := []series.SeriesInterface{s, valueSeries}
+		if linkable(parts) {
+			result.series[i] = appendRows(s, valueSeries)
+		} else {
+			result.series[i] = concatSeries(s.Name(), parts, []int{s.Len(), 1})
 		}
 	}
 
-	return df
+	// The new row has a null label
+	if result.index != nil {
+		labels := make([]series.SeriesInterface, len(result.index.series))
+		for i, s := range result.index.series {
+			labels[i] = concatSeries(s.Name(), []series.SeriesInterface{s, nil}, []int{s.Len(), 1})
+		}
+		result.index = newRowIndex(labels)
+	}
+
+	return result
 }
 
 // Select returns a new DataFrame with the selected columns.
 //
 // Select does not create a copy of the data, it only creates a new DataFrame
-// with references to the original data.
-// The columnNames can be a string, slice of strings, int, or slice of ints.
+// with references to the original data. Since operations don't modify Series,
+// changes to either DataFrame don't affect the other.
+// The columns can be selected as in GetColumnNames.
 func (df *DataFrame) Select(selectedColumn ...any) *DataFrame {
-	if len(df.series) == 0 {
-		return &DataFrame{}
+	if df.err != nil {
+		return &DataFrame{err: df.err}
 	}
-
-	// Check if all values are of the same type
-	if !allSameType(selectedColumn) {
-		fmt.Println("All values must be of the same type")
+	if len(df.series) == 0 {
 		return &DataFrame{}
 	}
 
 	columnNames, err := df.GetColumnNames(selectedColumn...)
 	if err != nil {
-		fmt.Println(err)
-		return &DataFrame{}
+		return &DataFrame{err: err}
 	}
 
 	newSeries := []series.SeriesInterface{}
@@ -460,46 +491,63 @@ func (df *DataFrame) Select(selectedColumn ...any) *DataFrame {
 			}
 		}
 	}
-	return &DataFrame{newSeries}
+	return &DataFrame{series: newSeries, index: df.index, parallelism: df.parallelism}
 }
 
 // GetColumnNames returns the column names based on the selected columns.
 //
-// The selectedColumns can be a string, slice of strings, int, or slice of ints.
+// The selectedColumns can be strings, slices of strings, ints, slices of ints or
+// ColumnSelectors such as ByRegex, ByType, Exclude and All, in any mix.
 //
-// Returns a slice of strings with the column names.
+// Returns a slice of strings with the column names, in the order they are selected,
+// without repeating a column selected more than once.
 // Error is returned if one of the columns do not exist.
 func (df *DataFrame) GetColumnNames(selectedColumns ...any) ([]string, error) {
-	if len(selectedColumns) == 0 {
-		return []string{}, nil
-	}
-
-	switch selectedColumns[0].(type) {
-	case []string, string:
-		columnNames := InterfaceToTypeSlice[string](selectedColumns)
-
-		// Check if all columns exist
-		allExist := df.allColumnsExist(columnNames)
-
-		if allExist {
-			return columnNames, nil
-		} else {
-			columns := df.findColumnsThatDontExist(columnNames)
-			return nil, errors.New("One of these columns do not exist: " + SprintfStringSlice(columns))
+	columnNames := []string{}
+	missing := []string{}
+	add := func(names ...string) {
+		for _, name := range names {
+			if !df.HasColumn(name) {
+				missing = append(missing, name)
+			} else if !slices.Contains(columnNames, name) {
+				columnNames = append(columnNames, name)
+			}
 		}
+	}
 
-	case []int, int:
-		columnIndexes := InterfaceToTypeSlice[int](selectedColumns)
-
-		columnNames := []string{}
-		for _, index := range columnIndexes {
-			if index < 0 || index >= len(df.series) {
-				return nil, errors.New("Index out of range: " + fmt.Sprint(index))
+	for _, selected := range selectedColumns {
+		switch v := selected.(type) {
+		case string:
+			add(v)
+		case []string:
+			add(v...)
+		case int, []int:
+			indexes, _ := InterfaceToTypeSlice[int](v)
+			for _, index := range indexes {
+				if index < 0 || index >= len(df.series) {
+					return nil, fmt.Errorf("%w: index out of range: %d", ErrColumnNotFound, index)
+				}
+				add(df.series[index].Name())
 			}
-			columnNames = append(columnNames, df.series[index].Name())
+		case ColumnSelector:
+			names, err := v(df)
+			if err != nil {
+				return nil, err
+			}
+			add(names...)
+		case []any:
+			names, err := df.GetColumnNames(v...)
+			if err != nil {
+				return nil, err
+			}
+			add(names...)
+		default:
+			return nil, fmt.Errorf("Unsupported column selector of type %T", selected)
 		}
-		return columnNames, nil
 	}
 
-	return []string{}, nil
+	if len(missing) > 0 {
+		return nil, columnNotFound(missing...)
+	}
+	return columnNames, nil
 }
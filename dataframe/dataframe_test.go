@@ -1,9 +1,16 @@
 package dataframe
 
 import (
+	"errors"
+	"math"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"teddy/dataframe/expr"
 	"teddy/dataframe/series"
 	"testing"
+	"time"
 )
 
 func TestNewDataFrame(t *testing.T) {
@@ -374,7 +381,6 @@ func TestTypedSeriesCreation(t *testing.T) {
 
 func TestMemoryUsage(t *testing.T) {
 	// Demonstrates memory usage differences between typed and generic series
-	// This isn't a real test, but demonstrates memory usage
 	// Create a DataFrame with typed and untyped series
 
 	// Small size for quick test
@@ -403,4 +409,1199 @@ func TestMemoryUsage(t *testing.T) {
 	if row1 != row2 || col1 != col2 {
 		t.Errorf("Expected same shape for both DataFrames")
 	}
+
+	// The untyped series boxes each value in an interface
+	typedBytes := df1.MemoryUsage(true).GetSeries("bytes").Get(0).(int)
+	untypedBytes := df2.MemoryUsage(true).GetSeries("bytes").Get(0).(int)
+	if typedBytes != size*8 || untypedBytes <= typedBytes {
+		t.Errorf("Expected the typed series to use %d bytes and less than the untyped one, got %d and %d", size*8, typedBytes, untypedBytes)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	// Tests stacking DataFrames with different columns and types
+	df1 := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jack"}),
+		series.NewIntSeries("Age", []int{35, 23}),
+	)
+	df2 := NewDataFrame(
+		series.NewFloat64Series("Age", []float64{48.5}),
+		series.NewStringSeries("City", []string{"London"}),
+	)
+
+	df, err := Concat(df1, df2)
+	if err != nil {
+		t.Fatalf("Error concatenating: %v", err)
+	}
+
+	row, col := df.Shape()
+	if row != 3 || col != 3 {
+		t.Errorf("Expected 3 rows and 3 columns, got %d rows and %d columns", row, col)
+	}
+
+	// Int and float columns are promoted to float
+	ageSeries := df.GetSeries("Age")
+	if _, ok := ageSeries.(*series.Float64Series); !ok {
+		t.Errorf("Expected Age to be promoted to Float64Series, got %T", ageSeries)
+	}
+	if ageSeries.Get(0) != 35.0 || ageSeries.Get(2) != 48.5 {
+		t.Errorf("Expected Age values [35 23 48.5], got %v", ageSeries.Values())
+	}
+
+	// Missing columns are filled with nulls
+	nameSeries := df.GetSeries("Name")
+	if !nameSeries.IsNull(2) || nameSeries.Get(2) != nil {
+		t.Errorf("Expected Name to be null in the last row, got %v", nameSeries.Get(2))
+	}
+	citySeries := df.GetSeries("City")
+	if !citySeries.IsNull(0) || citySeries.IsNull(2) {
+		t.Errorf("Expected City to be null only in the first two rows, got %v", citySeries.Values())
+	}
+
+	// Inner join keeps only the shared columns
+	df, err = ConcatWith(OptionsMap{"join": "inner"}, df1, df2)
+	if err != nil {
+		t.Fatalf("Error concatenating: %v", err)
+	}
+	if names := df.ColumnNames(); len(names) != 1 || names[0] != "Age" {
+		t.Errorf("Expected only the Age column, got %v", names)
+	}
+
+	if _, err := ConcatWith(OptionsMap{"join": "left"}, df1, df2); err == nil {
+		t.Errorf("Expected an error for an unknown join mode")
+	}
+	if _, err := ConcatWith(OptionsMap{"join": true}, df1, df2); err == nil {
+		t.Errorf("Expected an error for a join mode that is not a string")
+	}
+}
+
+func TestHConcat(t *testing.T) {
+	// Tests placing DataFrames side by side with duplicate column names
+	df1 := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jack"}),
+		series.NewIntSeries("Age", []int{35, 23}),
+	)
+	df2 := NewDataFrame(
+		series.NewIntSeries("Age", []int{36, 24}),
+		series.NewStringSeries("City", []string{"London", "Paris"}),
+	)
+
+	df, err := HConcatWith(OptionsMap{"suffixes": []string{"_2020", "_2021"}}, df1, df2)
+	if err != nil {
+		t.Fatalf("Error concatenating: %v", err)
+	}
+
+	expected := []string{"Name", "Age_2020", "Age_2021", "City"}
+	if !slices.Equal(df.ColumnNames(), expected) {
+		t.Errorf("Expected columns %v, got %v", expected, df.ColumnNames())
+	}
+
+	// The inputs keep their original column names
+	if !df1.HasColumn("Age") || !df2.HasColumn("Age") {
+		t.Errorf("Expected the input DataFrames to be unchanged")
+	}
+
+	// Heights must match
+	df3 := NewDataFrame(series.NewIntSeries("Id", []int{1, 2, 3}))
+	if _, err := HConcat(df1, df3); err == nil {
+		t.Errorf("Expected an error for DataFrames of different heights")
+	}
+
+	// Suffixes of the wrong type are an error
+	if _, err := HConcatWith(OptionsMap{"suffixes": "x"}, df1, df2); err == nil {
+		t.Errorf("Expected an error for suffixes that are not a []string")
+	}
+}
+
+func TestPivot(t *testing.T) {
+	// Tests reshaping a long DataFrame into a wide one
+	df := NewDataFrame(
+		series.NewStringSeries("City", []string{"London", "London", "Paris", "Paris", "Tokyo"}),
+		series.NewIntSeries("Year", []int{2021, 2020, 2020, 2021, 2021}),
+		series.NewFloat64Series("Sales", []float64{1.5, 1.0, 2.0, 2.5, 3.0}),
+	)
+
+	wide, err := df.Pivot([]string{"City"}, "Year", "Sales")
+	if err != nil {
+		t.Fatalf("Error pivoting: %v", err)
+	}
+
+	expected := []string{"City", "2020", "2021"}
+	if !slices.Equal(wide.ColumnNames(), expected) {
+		t.Errorf("Expected columns %v, got %v", expected, wide.ColumnNames())
+	}
+	if wide.GetSeries("2020").Get(0) != 1.0 || wide.GetSeries("2021").Get(0) != 1.5 {
+		t.Errorf("Expected London values [1 1.5], got %v", []any{wide.GetSeries("2020").Get(0), wide.GetSeries("2021").Get(0)})
+	}
+
+	// Tokyo has no 2020 value
+	if !wide.GetSeries("2020").IsNull(2) {
+		t.Errorf("Expected Tokyo 2020 to be null, got %v", wide.GetSeries("2020").Get(2))
+	}
+
+	// Duplicate cells are an error
+	df = df.AddRow([]any{"Tokyo", 2021, 4.0})
+	if _, err := df.Pivot([]string{"City"}, "Year", "Sales"); err == nil {
+		t.Errorf("Expected an error for duplicate entries")
+	}
+
+	// Index values containing the key separator are different rows
+	separated := NewDataFrame(
+		series.NewStringSeries("A", []string{"a|b", "a"}),
+		series.NewStringSeries("B", []string{"c", "b|c"}),
+		series.NewStringSeries("Kind", []string{"x", "x"}),
+		series.NewIntSeries("Value", []int{1, 2}),
+	)
+	if wide, err := separated.Pivot([]string{"A", "B"}, "Kind", "Value"); err != nil || wide.Height() != 2 {
+		t.Errorf("Expected 2 rows, got %v, %v", wide, err)
+	}
+}
+
+func TestMelt(t *testing.T) {
+	// Tests reshaping a wide DataFrame into a long one
+	df := NewDataFrame(
+		series.NewStringSeries("Sensor", []string{"A", "B"}),
+		series.NewIntSeries("Mon", []int{1, 2}),
+		series.NewFloat64Series("Tue", []float64{3.5, 4.5}),
+	)
+
+	long, err := df.Melt([]string{"Sensor"}, nil, "Day", "Reading")
+	if err != nil {
+		t.Fatalf("Error melting: %v", err)
+	}
+
+	row, col := long.Shape()
+	if row != 4 || col != 3 {
+		t.Errorf("Expected 4 rows and 3 columns, got %d rows and %d columns", row, col)
+	}
+
+	expectedSensors := []any{"A", "B", "A", "B"}
+	expectedDays := []any{"Mon", "Mon", "Tue", "Tue"}
+	expectedReadings := []any{1.0, 2.0, 3.5, 4.5}
+	if !slices.Equal(long.GetSeries("Sensor").Values(), expectedSensors) {
+		t.Errorf("Expected sensors %v, got %v", expectedSensors, long.GetSeries("Sensor").Values())
+	}
+	if !slices.Equal(long.GetSeries("Day").Values(), expectedDays) {
+		t.Errorf("Expected days %v, got %v", expectedDays, long.GetSeries("Day").Values())
+	}
+	if !slices.Equal(long.GetSeries("Reading").Values(), expectedReadings) {
+		t.Errorf("Expected readings %v, got %v", expectedReadings, long.GetSeries("Reading").Values())
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	// Tests turning rows into columns with type unification
+	df := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jack"}),
+		series.NewIntSeries("Age", []int{35, 23}),
+		series.NewFloat64Series("Height", []float64{5.8, 6.1}),
+	)
+
+	transposed, err := df.Transpose(OptionsMap{"header_column": "Name"})
+	if err != nil {
+		t.Fatalf("Error transposing: %v", err)
+	}
+
+	expected := []string{"Name", "John", "Jack"}
+	if !slices.Equal(transposed.ColumnNames(), expected) {
+		t.Errorf("Expected columns %v, got %v", expected, transposed.ColumnNames())
+	}
+
+	john := transposed.GetSeries("John")
+	if _, ok := john.(*series.Float64Series); !ok {
+		t.Errorf("Expected John to be a Float64Series, got %T", john)
+	}
+	if !slices.Equal(john.Values(), []any{35.0, 5.8}) {
+		t.Errorf("Expected John values [35 5.8], got %v", john.Values())
+	}
+
+	// Without a header column the strings prevent a common type
+	transposed, err = df.Transpose()
+	if err != nil {
+		t.Fatalf("Error transposing: %v", err)
+	}
+	if _, ok := transposed.GetSeries("Column 0").(*series.GenericSeries); !ok {
+		t.Errorf("Expected Column 0 to be a GenericSeries, got %T", transposed.GetSeries("Column 0"))
+	}
+}
+
+func TestExplodeAndImplode(t *testing.T) {
+	// Tests splitting multi-valued fields into rows and joining them back
+	df := NewDataFrame(
+		series.NewIntSeries("Id", []int{1, 2, 3}),
+		series.NewStringSeries("Tags", []string{"a;b;c", "d", "e;f"}),
+	)
+
+	exploded, err := df.Explode("Tags", ";")
+	if err != nil {
+		t.Fatalf("Error exploding: %v", err)
+	}
+
+	expectedIds := []any{1, 1, 1, 2, 3, 3}
+	expectedTags := []any{"a", "b", "c", "d", "e", "f"}
+	if _, ok := exploded.GetSeries("Id").(*series.IntSeries); !ok {
+		t.Errorf("Expected Id to stay an IntSeries, got %T", exploded.GetSeries("Id"))
+	}
+	if !slices.Equal(exploded.GetSeries("Id").Values(), expectedIds) {
+		t.Errorf("Expected ids %v, got %v", expectedIds, exploded.GetSeries("Id").Values())
+	}
+	if !slices.Equal(exploded.GetSeries("Tags").Values(), expectedTags) {
+		t.Errorf("Expected tags %v, got %v", expectedTags, exploded.GetSeries("Tags").Values())
+	}
+
+	imploded, err := exploded.Implode([]string{"Id"}, "Tags", ";")
+	if err != nil {
+		t.Fatalf("Error imploding: %v", err)
+	}
+	if !slices.Equal(imploded.GetSeries("Tags").Values(), df.GetSeries("Tags").Values()) {
+		t.Errorf("Expected tags %v, got %v", df.GetSeries("Tags").Values(), imploded.GetSeries("Tags").Values())
+	}
+}
+
+func TestSample(t *testing.T) {
+	// Tests reproducible random sampling
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+	df := NewDataFrame(
+		series.NewIntSeries("Id", values),
+		series.NewStringSeries("Group", slices.Repeat([]string{"a", "a", "a", "b"}, 25)),
+	)
+
+	sample1, err := df.Sample(OptionsMap{"n": 10, "seed": 42})
+	if err != nil {
+		t.Fatalf("Error sampling: %v", err)
+	}
+	sample2, _ := df.Sample(OptionsMap{"n": 10, "seed": 42})
+	if sample1.Height() != 10 {
+		t.Errorf("Expected 10 rows, got %d", sample1.Height())
+	}
+	if !slices.Equal(sample1.GetSeries("Id").Values(), sample2.GetSeries("Id").Values()) {
+		t.Errorf("Expected the same sample for the same seed")
+	}
+
+	sample3, _ := df.Sample(OptionsMap{"fraction": 0.25, "seed": 1})
+	if sample3.Height() != 25 {
+		t.Errorf("Expected 25 rows, got %d", sample3.Height())
+	}
+
+	if _, err := df.Sample(OptionsMap{"n": 200}); err == nil {
+		t.Errorf("Expected an error when sampling more rows than available without replacement")
+	}
+	sample4, _ := df.Sample(OptionsMap{"n": 200, "replace": true, "seed": 1})
+	if sample4.Height() != 200 {
+		t.Errorf("Expected 200 rows, got %d", sample4.Height())
+	}
+
+	// Each group keeps its proportion
+	stratified, err := df.StratifiedSample([]string{"Group"}, 0.2, OptionsMap{"seed": 7})
+	if err != nil {
+		t.Fatalf("Error sampling: %v", err)
+	}
+	counts := map[any]int{}
+	for _, group := range stratified.GetSeries("Group").Values() {
+		counts[group]++
+	}
+	if counts["a"] != 15 || counts["b"] != 5 {
+		t.Errorf("Expected 15 a and 5 b rows, got %v", counts)
+	}
+
+	train, test, err := df.TrainTestSplit(0.2, OptionsMap{"seed": 3})
+	if err != nil {
+		t.Fatalf("Error splitting: %v", err)
+	}
+	if train.Height() != 80 || test.Height() != 20 {
+		t.Errorf("Expected 80 train and 20 test rows, got %d and %d", train.Height(), test.Height())
+	}
+}
+
+func TestRows(t *testing.T) {
+	// Tests iterating rows with typed getters
+	df := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jack", "Tyler"}),
+		series.NewIntSeries("Age", []int{35, 23, 48}),
+		series.NewFloat64Series("Height", []float64{5.8, 6.1, 5.9}),
+	)
+
+	names := []string{}
+	totalAge := 0
+	totalHeight := 0.0
+	for i, row := range df.Rows() {
+		if row.Index() != i {
+			t.Errorf("Expected row index %d, got %d", i, row.Index())
+		}
+		names = append(names, row.String("Name"))
+		totalAge += row.Int("Age")
+		totalHeight += row.Float("Height")
+	}
+
+	if !slices.Equal(names, []string{"John", "Jack", "Tyler"}) {
+		t.Errorf("Expected names [John Jack Tyler], got %v", names)
+	}
+	if totalAge != 106 {
+		t.Errorf("Expected total age 106, got %d", totalAge)
+	}
+	if totalHeight < 17.79 || totalHeight > 17.81 {
+		t.Errorf("Expected total height 17.8, got %f", totalHeight)
+	}
+
+	// Getters convert between types
+	for _, row := range df.Rows() {
+		if row.Float("Age") != 35.0 || row.String("Age") != "35" {
+			t.Errorf("Expected Age to convert to 35.0 and \"35\", got %v and %v", row.Float("Age"), row.String("Age"))
+		}
+		break
+	}
+
+	// Rows can be kept after their iteration step
+	rows := slices.Collect(func(yield func(Row) bool) {
+		for _, row := range df.Rows() {
+			if !yield(row) {
+				return
+			}
+		}
+	})
+	if rows[0].String("Name") != "John" || rows[2].String("Name") != "Tyler" {
+		t.Errorf("Expected each row to keep its position, got %s and %s", rows[0].String("Name"), rows[2].String("Name"))
+	}
+
+	// Getters read the chunk holding the row
+	chunked := NewDataFrame(series.Link("Age", series.NewIntSeries("Age", []int{1, 2}), series.NewIntSeries("Age", []int{3})))
+	ages := []int{}
+	for _, row := range chunked.Rows() {
+		ages = append(ages, row.Int("Age"))
+	}
+	if !slices.Equal(ages, []int{1, 2, 3}) {
+		t.Errorf("Expected ages [1 2 3], got %v", ages)
+	}
+
+	// Typed series iterators
+	sum := 0
+	for _, age := range df.GetSeries("Age").(*series.IntSeries).All() {
+		sum += age
+	}
+	if sum != 106 {
+		t.Errorf("Expected sum 106, got %d", sum)
+	}
+}
+
+func TestStructs(t *testing.T) {
+	// Tests converting between slices of structs and DataFrames
+	type person struct {
+		Name     string    `df:"name"`
+		Age      int       `df:"age"`
+		Height   *float64  `df:"height"`
+		Birthday time.Time `df:"birthday"`
+		Secret   string    `df:"-"`
+		internal int
+	}
+
+	height := 5.8
+	birthday := time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC)
+	people := []person{
+		{Name: "John", Age: 35, Height: &height, Birthday: birthday, Secret: "x"},
+		{Name: "Jack", Age: 23, Birthday: birthday},
+	}
+
+	df, err := FromStructs(people)
+	if err != nil {
+		t.Fatalf("Error converting from structs: %v", err)
+	}
+
+	expected := []string{"name", "age", "height", "birthday"}
+	if !slices.Equal(df.ColumnNames(), expected) {
+		t.Errorf("Expected columns %v, got %v", expected, df.ColumnNames())
+	}
+	if _, ok := df.GetSeries("age").(*series.IntSeries); !ok {
+		t.Errorf("Expected age to be an IntSeries, got %T", df.GetSeries("age"))
+	}
+	if _, ok := df.GetSeries("birthday").(*series.TimeSeries); !ok {
+		t.Errorf("Expected birthday to be a TimeSeries, got %T", df.GetSeries("birthday"))
+	}
+	if df.GetSeries("height").Get(0) != 5.8 || !df.GetSeries("height").IsNull(1) {
+		t.Errorf("Expected heights [5.8 null], got %v", df.GetSeries("height").Values())
+	}
+
+	var out []*person
+	if err := df.ToStructs(&out); err != nil {
+		t.Fatalf("Error converting to structs: %v", err)
+	}
+	if len(out) != 2 || out[0].Name != "John" || out[1].Age != 23 || *out[0].Height != 5.8 || out[1].Height != nil {
+		t.Errorf("Expected the structs to round trip, got %+v and %+v", out[0], out[1])
+	}
+	if !out[0].Birthday.Equal(birthday) {
+		t.Errorf("Expected birthday %v, got %v", birthday, out[0].Birthday)
+	}
+
+	// Mismatched types are reported
+	type badPerson struct {
+		Age string `df:"age"`
+	}
+	var bad []badPerson
+	if err := df.ToStructs(&bad); err == nil {
+		t.Errorf("Expected an error for mismatched types")
+	}
+
+	// Nulls can only be assigned to pointers
+	type heightOnly struct {
+		Height float64 `df:"height"`
+	}
+	var heights []heightOnly
+	if err := df.ToStructs(&heights); err == nil {
+		t.Errorf("Expected an error for assigning null to a non-pointer field")
+	}
+
+	// Unsigned values that don't fit in an int are reported
+	type counter struct {
+		Count uint64 `df:"count"`
+	}
+	if _, err := FromStructs([]counter{{Count: math.MaxUint64}}); !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion for an overflowing uint, got %v", err)
+	}
+
+	// Two fields can't have the same column name
+	type duplicate struct {
+		Name  string `df:"name"`
+		Alias string `df:"name"`
+	}
+	if _, err := FromStructs([]duplicate{{}}); err == nil {
+		t.Errorf("Expected an error for duplicate column names")
+	}
+	var duplicates []duplicate
+	if err := df.ToStructs(&duplicates); err == nil {
+		t.Errorf("Expected an error for duplicate column names")
+	}
+}
+
+func TestRecords(t *testing.T) {
+	// Tests converting between records and DataFrames
+	records := []map[string]any{
+		{"name": "John", "age": 35},
+		{"name": "Jack", "city": "London"},
+	}
+
+	df := FromRecords(records, OptionsMap{"columns": []string{"name"}})
+
+	expected := []string{"name", "age", "city"}
+	if !slices.Equal(df.ColumnNames(), expected) {
+		t.Errorf("Expected columns %v, got %v", expected, df.ColumnNames())
+	}
+	if _, ok := df.GetSeries("age").(*series.IntSeries); !ok {
+		t.Errorf("Expected age to be an IntSeries, got %T", df.GetSeries("age"))
+	}
+	if !df.GetSeries("age").IsNull(1) || !df.GetSeries("city").IsNull(0) {
+		t.Errorf("Expected missing keys to be null")
+	}
+
+	out, err := df.ToRecords()
+	if err != nil {
+		t.Fatalf("Error converting to records: %v", err)
+	}
+	if len(out) != 2 || out[0]["name"] != "John" || out[0]["age"] != 35 || out[1]["city"] != "London" {
+		t.Errorf("Expected the records to round trip, got %v", out)
+	}
+	if value, ok := out[1]["age"]; !ok || value != nil {
+		t.Errorf("Expected a nil age in the second record, got %v", value)
+	}
+
+	if FromRecords(records, OptionsMap{"columns": "name"}).Err() == nil {
+		t.Errorf("Expected an error for a columns option that is not a []string")
+	}
+	if FromRecords(records, OptionsMap{"columns": []string{"name", "name"}}).Err() == nil {
+		t.Errorf("Expected an error for a duplicate column")
+	}
+	if _, err := df.Select("missing").ToRecords(); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("Expected the error of the DataFrame, got %v", err)
+	}
+}
+
+// recordsOf returns the records of a DataFrame, or its error, for test messages
+func recordsOf(df *DataFrame) any {
+	records, err := df.ToRecords()
+	if err != nil {
+		return err
+	}
+	return records
+}
+
+func TestErrors(t *testing.T) {
+	// Tests that errors are carried through chained operations
+	df := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jack"}),
+		series.NewStringSeries("Age", []string{"35", "unknown"}),
+	)
+
+	selected := df.Select("Name", "Missing")
+	if !errors.Is(selected.Err(), ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", selected.Err())
+	}
+
+	// The first error is kept and later operations do nothing
+	df = df.AddSeries(series.NewIntSeries("Id", []int{1, 2, 3})).DropColumn("Missing")
+	if !errors.Is(df.Err(), ErrLengthMismatch) {
+		t.Errorf("Expected ErrLengthMismatch, got %v", df.Err())
+	}
+
+	df = NewDataFrame(series.NewStringSeries("Age", []string{"35", "unknown"})).AsType("Age", "int")
+	if !errors.Is(df.Err(), ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", df.Err())
+	}
+
+	df = NewDataFrame(series.NewStringSeries("Age", []string{"35"})).AddRow([]any{"1", "2"})
+	if !errors.Is(df.Err(), ErrLengthMismatch) {
+		t.Errorf("Expected ErrLengthMismatch, got %v", df.Err())
+	}
+
+	// Mixed selectors are reported instead of exiting
+	if _, err := NewDataFrame().GetColumnNames("Name", 1); err == nil {
+		t.Errorf("Expected an error for mixed column selectors")
+	}
+}
+
+func TestCopyOnWrite(t *testing.T) {
+	// Tests that operations don't change the DataFrame they are called on
+	df := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jack", "Jill"}),
+		series.NewIntSeries("Age", []int{35, 40, 28}),
+	)
+
+	changed := df.Rename("Name", "First").DropRow(0).AddRow([]any{"Jane", 2.5}).DropColumn("First")
+	if changed.Width() != 1 || changed.Height() != 3 || changed.GetSeries("Age").Type() != float64Type {
+		t.Errorf("Unexpected result %v", changed.ColumnNames())
+	}
+	if !slices.Equal(df.ColumnNames(), []string{"Name", "Age"}) || df.Height() != 3 {
+		t.Errorf("Expected the original DataFrame to be unchanged, got %v", df.ColumnNames())
+	}
+	if df.GetSeries("Age").Type() != intType || df.GetSeries("Age").Get(0) != 35 {
+		t.Errorf("Expected the original Age column to be unchanged")
+	}
+
+	// Selected DataFrames share the series but are not affected by each other
+	selected := df.Select("Age")
+	selected.AsType("Age", "string")
+	if selected.GetSeries("Age").Type() != intType {
+		t.Errorf("Expected AsType to return a new DataFrame")
+	}
+
+	// In place mode changes the DataFrame itself
+	df.SetInPlace(true).DropRow(0).Rename("Name", "First")
+	if df.Height() != 2 || !df.HasColumn("First") {
+		t.Errorf("Expected the DataFrame to be changed in place, got %v", df.ColumnNames())
+	}
+	if selected.Height() != 3 || !slices.Equal(selected.GetSeries("Age").Values(), []any{35, 40, 28}) {
+		t.Errorf("Expected the selected DataFrame to be unchanged, got %v", selected.GetSeries("Age").Values())
+	}
+
+	// Changed columns don't alias the shared Series
+	df.AsType("Age", "float64")
+	if df.GetSeries("Age") == selected.GetSeries("Age") || selected.GetSeries("Age").Type() != intType {
+		t.Errorf("Expected the in place changes to copy the shared Age column")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	// Tests deriving, comparing, casting to and reading with a Schema
+	schema, err := NewSchema(
+		Field{Name: "Age", Type: "float", Nullable: true},
+		Field{Name: "Name", Type: "string"},
+	)
+	if err != nil {
+		t.Fatalf("Error creating schema: %v", err)
+	}
+
+	df := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jane"}),
+		series.NewIntSeries("Age", []int{25, 30}),
+		series.NewBoolSeries("IsStudent", []bool{true, false}),
+	)
+	if diff := schema.Diff(df.Schema()); len(diff) != 5 {
+		t.Errorf("Expected 5 differences, got %v", diff)
+	}
+
+	cast := df.CastTo(schema)
+	if cast.Err() != nil {
+		t.Fatalf("Error casting: %v", cast.Err())
+	}
+	if !slices.Equal(cast.ColumnNames(), []string{"Age", "Name"}) || cast.GetSeries("Age").Get(1) != 30.0 {
+		t.Errorf("Unexpected result %v", recordsOf(cast))
+	}
+	if diff := schema.Diff(cast.Schema()); len(diff) != 1 {
+		t.Errorf("Expected only the nullability to differ, got %v", diff)
+	}
+
+	// Non nullable columns can't have nulls
+	nullable := FromRecords([]map[string]any{{"Name": "John", "Age": 1}, {"Age": 2}})
+	if err := nullable.CastTo(schema).Err(); !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", err)
+	}
+
+	// JSON round trip
+	data, err := schema.ToJSON()
+	if err != nil {
+		t.Fatalf("Error writing schema: %v", err)
+	}
+	loaded, err := SchemaFromJSON(data)
+	if err != nil || !loaded.Equal(schema) {
+		t.Errorf("Expected the schema to round trip, got %v, %v", loaded, err)
+	}
+	if _, err := SchemaFromJSON([]byte(`{"fields": [{"name": "a", "type": "decimal"}]}`)); err == nil {
+		t.Errorf("Expected an error for an unknown type")
+	}
+
+	// Reading CSV with a schema
+	read, err := Read().
+		FromString("Name,Age,Extra\nJohn,25,x\nJane,,y").
+		Option("header", true).
+		Schema(schema).
+		Load()
+	if err != nil {
+		t.Fatalf("Error reading CSV: %v", err)
+	}
+	if !read.Schema().Equal(schema) || !read.GetSeries("Age").IsNull(1) {
+		t.Errorf("Expected the CSV to match the schema, got %v", read.Schema().Diff(schema))
+	}
+
+	_, err = Read().FromString("Name,Age\nJohn,old").Option("header", true).Schema(schema).Load()
+	if !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", err)
+	}
+
+	// Empty values of a string column are empty strings, not nulls
+	read, err = Read().FromString("Name,Age\n,25").Option("header", true).Schema(schema).Load()
+	if err != nil || read.GetSeries("Name").IsNull(0) || read.GetSeries("Name").Get(0) != "" {
+		t.Errorf("Expected an empty name, got %v, %v", read, err)
+	}
+
+	// Nil schemas can be compared
+	var none *Schema
+	if !none.Equal(nil) || none.Equal(schema) || schema.Equal(none) {
+		t.Errorf("Expected only nil schemas to equal nil")
+	}
+	if diff := schema.Diff(nil); len(diff) != 2 {
+		t.Errorf("Expected both fields to be missing, got %v", diff)
+	}
+	if diff := none.Diff(schema); len(diff) != 2 {
+		t.Errorf("Expected both fields to be unexpected, got %v", diff)
+	}
+	if err := df.CastTo(nil).Err(); err == nil {
+		t.Errorf("Expected an error casting to a nil schema")
+	}
+}
+
+func TestWithColumnsAndFilter(t *testing.T) {
+	// Tests adding columns and filtering rows with expressions
+	df := NewDataFrame(
+		series.NewStringSeries("Name", []string{"John", "Jane", "Bob"}),
+		series.NewIntSeries("Price", []int{10, 20, 30}),
+		series.NewIntSeries("Quantity", []int{3, 1, 2}),
+	)
+
+	result := df.WithColumns(
+		expr.Col("Price").Mul(expr.Col("Quantity")).Alias("Total"),
+		expr.Col("Name").Upper(),
+	).Filter(expr.Col("Total").Gt(25))
+
+	if result.Err() != nil {
+		t.Fatalf("Error: %v", result.Err())
+	}
+	if !slices.Equal(result.ColumnNames(), []string{"Price", "Quantity", "Total", "Name"}) {
+		t.Errorf("Unexpected columns %v", result.ColumnNames())
+	}
+	if !slices.Equal(result.GetSeries("Name").Values(), []any{"JOHN", "BOB"}) {
+		t.Errorf("Expected [JOHN BOB], got %v", result.GetSeries("Name").Values())
+	}
+	if result.GetSeries("Total").Type() != intType {
+		t.Errorf("Expected Total to be int, got %v", result.GetSeries("Total").Type())
+	}
+	if df.Width() != 3 {
+		t.Errorf("Expected the original DataFrame to be unchanged")
+	}
+
+	if err := df.Filter(expr.Col("Missing").Gt(1)).Err(); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", err)
+	}
+	if err := df.Filter(expr.Col("Price")).Err(); !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", err)
+	}
+}
+
+func TestEval(t *testing.T) {
+	// Tests computed columns from formulas, with type checks against the schema
+	df := NewDataFrame(
+		series.NewStringSeries("Name", []string{"john", "jane", "bob"}),
+		series.NewFloat64Series("Price", []float64{10, 20, 40}),
+		series.NewFloat64Series("Cost", []float64{7.5, 25, 10}),
+		series.NewStringSeriesWithNulls("Nick", []string{"", "JJ", ""}, []bool{true, false, true}),
+	)
+
+	result := df.Eval("Margin = round((Price - Cost) / Price * 100, 1)").
+		Eval("Loss = abs(-(Price - Cost))").
+		Eval("Label = coalesce(Nick, upper(Name))")
+	if result.Err() != nil {
+		t.Fatalf("Error: %v", result.Err())
+	}
+	if !slices.Equal(result.GetSeries("Margin").Values(), []any{25.0, -25.0, 75.0}) {
+		t.Errorf("Unexpected Margin %v", result.GetSeries("Margin").Values())
+	}
+	if !slices.Equal(result.GetSeries("Loss").Values(), []any{2.5, 5.0, 30.0}) {
+		t.Errorf("Unexpected Loss %v", result.GetSeries("Loss").Values())
+	}
+	if !slices.Equal(result.GetSeries("Label").Values(), []any{"JOHN", "JJ", "BOB"}) {
+		t.Errorf("Unexpected Label %v", result.GetSeries("Label").Values())
+	}
+
+	if err := df.Eval("X = Price + Name").Err(); !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", err)
+	}
+	if err := df.Eval("X = Missing * 2").Err(); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", err)
+	}
+	var syntaxErr *expr.SyntaxError
+	if err := df.Eval("X = (Price - Cost"); !errors.As(err.Err(), &syntaxErr) || syntaxErr.Pos != 17 {
+		t.Errorf("Expected a syntax error at position 17, got %v", err.Err())
+	}
+}
+
+func TestIndex(t *testing.T) {
+	// Tests label lookups and that the index follows the rows through operations
+	df := NewDataFrame(
+		series.NewStringSeries("City", []string{"Oslo", "Paris", "Rome", "Lima"}),
+		series.NewIntSeries("Year", []int{2021, 2019, 2023, 2020}),
+		series.NewFloat64Series("Rain", []float64{7.6, 6.4, 8.7, 0.6}),
+	).SetIndex("Year")
+
+	if df.Err() != nil {
+		t.Fatalf("Error: %v", df.Err())
+	}
+	if !slices.Equal(df.ColumnNames(), []string{"City", "Rain"}) || !slices.Equal(df.IndexNames(), []string{"Year"}) {
+		t.Errorf("Unexpected columns %v and index %v", df.ColumnNames(), df.IndexNames())
+	}
+	if row := df.Loc(2023); !slices.Equal(row.GetSeries("City").Values(), []any{"Rome"}) {
+		t.Errorf("Expected Rome, got %v", row.GetSeries("City").Values())
+	}
+	if cities := df.LocRange(2020, 2021).GetSeries("City").Values(); !slices.Equal(cities, []any{"Lima", "Oslo"}) {
+		t.Errorf("Expected [Lima Oslo], got %v", cities)
+	}
+	if cities := df.LocRange(nil, 2020).GetSeries("City").Values(); !slices.Equal(cities, []any{"Paris", "Lima"}) {
+		t.Errorf("Expected [Paris Lima], got %v", cities)
+	}
+
+	// The index is kept by Select, Filter, Sort and DropRows
+	result := df.Select("City").Filter(expr.Col("City").Ne("Oslo")).Sort([]string{"City"}).DropRows(0)
+	if row := result.Loc(2019); row.Err() != nil || row.GetSeries("City").Get(0) != "Paris" {
+		t.Errorf("Expected Paris, got %v", row.Err())
+	}
+	if err := result.Loc(2020).Err(); !errors.Is(err, ErrLabelNotFound) {
+		t.Errorf("Expected ErrLabelNotFound, got %v", err)
+	}
+	reset := result.ResetIndex()
+	if !slices.Equal(reset.ColumnNames(), []string{"Year", "City"}) || !slices.Equal(reset.GetSeries("Year").Values(), []any{2019, 2023}) {
+		t.Errorf("Unexpected reset DataFrame %v", recordsOf(reset))
+	}
+
+	// Repeated labels and multi-column labels
+	repeated := NewDataFrame(
+		series.NewStringSeries("Team", []string{"a", "b", "a"}),
+		series.NewIntSeries("Round", []int{1, 1, 2}),
+		series.NewIntSeries("Score", []int{3, 4, 5}),
+	)
+	if scores := repeated.SetIndex("Team").Loc("a").GetSeries("Score").Values(); !slices.Equal(scores, []any{3, 5}) {
+		t.Errorf("Expected [3 5], got %v", scores)
+	}
+	if scores := repeated.SetIndex("Team", "Round").Loc([]any{"a", 2}).GetSeries("Score").Values(); !slices.Equal(scores, []any{5}) {
+		t.Errorf("Expected [5], got %v", scores)
+	}
+	if err := repeated.Loc("a").Err(); !errors.Is(err, ErrNoIndex) {
+		t.Errorf("Expected ErrNoIndex, got %v", err)
+	}
+
+	// Labels of another type match the same way whether labels are unique or repeated
+	unique := repeated.SetIndex("Score")
+	byRound := repeated.SetIndex("Round")
+	for _, label := range []any{"5", "5.0", 5.0} {
+		if scores := unique.Loc(label).GetSeries("Round").Values(); !slices.Equal(scores, []any{2}) {
+			t.Errorf("Expected label %#v to find round [2], got %v", label, scores)
+		}
+	}
+	for _, label := range []any{"1", "1.0", 1.0} {
+		if scores := byRound.Loc(label).GetSeries("Score").Values(); !slices.Equal(scores, []any{3, 4}) {
+			t.Errorf("Expected label %#v to find scores [3 4], got %v", label, scores)
+		}
+	}
+}
+
+func TestColumnSelectors(t *testing.T) {
+	// Tests selecting, reordering and renaming columns with selectors
+	df := NewDataFrame(
+		series.NewIntSeries("id", []int{1, 2}),
+		series.NewStringSeries("addr_city", []string{"Oslo", "Rome"}),
+		series.NewStringSeries("addr_zip", []string{"0150", "00100"}),
+		series.NewFloat64Series("score", []float64{1.5, 2.5}),
+	)
+
+	tests := []struct {
+		selected []any
+		expected []string
+	}{
+		{[]any{ByRegex("^addr_")}, []string{"addr_city", "addr_zip"}},
+		{[]any{ByType("int", "float64")}, []string{"id", "score"}},
+		{[]any{"score", Exclude("score", 0)}, []string{"score", "addr_city", "addr_zip"}},
+		{[]any{2, "id", All()}, []string{"addr_zip", "id", "addr_city", "score"}},
+	}
+	for _, test := range tests {
+		if names := df.Select(test.selected...).ColumnNames(); !slices.Equal(names, test.expected) {
+			t.Errorf("Select(%v): expected %v, got %v", test.selected, test.expected, names)
+		}
+	}
+	if names := df.DropColumn(ByRegex("^addr_")).ColumnNames(); !slices.Equal(names, []string{"id", "score"}) {
+		t.Errorf("Expected [id score], got %v", names)
+	}
+	if err := df.Select(ByRegex("(")).Err(); err == nil {
+		t.Errorf("Expected an error for an invalid regex")
+	}
+
+	result := df.InsertSeries(1, series.NewBoolSeries("active", []bool{true, false})).
+		ReorderColumns("score", ByRegex("zip")).
+		RenameWith(strings.ToUpper).
+		RenameWith(map[string]string{"ADDR_CITY": "CITY"})
+	expected := []string{"SCORE", "ADDR_ZIP", "ID", "ACTIVE", "CITY"}
+	if result.Err() != nil || !slices.Equal(result.ColumnNames(), expected) {
+		t.Errorf("Expected %v, got %v, %v", expected, result.ColumnNames(), result.Err())
+	}
+	if err := df.RenameWith(func(string) string { return "x" }).Err(); err == nil {
+		t.Errorf("Expected an error for duplicate names")
+	}
+	if err := df.InsertSeries(1, series.NewIntSeries("short", []int{1})).Err(); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("Expected ErrLengthMismatch, got %v", err)
+	}
+
+	// In place mode reorders the DataFrame itself
+	inPlace := df.Select(All()).SetInPlace(true)
+	inPlace.ReorderColumns("score")
+	if expected := []string{"score", "id", "addr_city", "addr_zip"}; !slices.Equal(inPlace.ColumnNames(), expected) {
+		t.Errorf("Expected %v in place, got %v", expected, inPlace.ColumnNames())
+	}
+}
+
+func TestWindow(t *testing.T) {
+	// Tests window functions over partitions, keeping the row order
+	df := NewDataFrame(
+		series.NewStringSeries("customer", []string{"a", "b", "a", "a", "b"}),
+		series.NewIntSeries("day", []int{3, 1, 1, 2, 2}),
+		series.NewIntSeries("amount", []int{30, 5, 10, 10, 7}),
+	)
+
+	result := df.Window([]string{"customer"}, []string{"day"}, []WindowFunction{
+		RowNumber(),
+		Lag("amount", 1).Alias("previous"),
+		Lead("day", 1),
+		FirstValue("amount"),
+		LastValue("day"),
+		CumSum("amount").Alias("running"),
+	})
+	if result.Err() != nil {
+		t.Fatalf("Error: %v", result.Err())
+	}
+
+	expected := map[string][]any{
+		"row_number":   {3, 1, 1, 2, 2},
+		"previous":     {10, nil, nil, 10, 5},
+		"day_lead":     {nil, 2, 2, 3, nil},
+		"amount_first": {10, 5, 10, 10, 5},
+		"day_last":     {3, 2, 3, 3, 2},
+		"running":      {50, 5, 10, 20, 12},
+	}
+	for column, values := range expected {
+		if got := result.GetSeries(column).Values(); !slices.Equal(got, values) {
+			t.Errorf("%s: expected %v, got %v", column, values, got)
+		}
+	}
+
+	// Ranks by amount, highest first, over all rows
+	ranked := df.Window(nil, []string{"amount"}, []WindowFunction{Rank(), DenseRank(), PercentRank()}, OptionsMap{"descending": true})
+	if got := ranked.GetSeries("rank").Values(); !slices.Equal(got, []any{1, 5, 2, 2, 4}) {
+		t.Errorf("rank: got %v", got)
+	}
+	if got := ranked.GetSeries("dense_rank").Values(); !slices.Equal(got, []any{1, 4, 2, 2, 3}) {
+		t.Errorf("dense_rank: got %v", got)
+	}
+	if got := ranked.GetSeries("percent_rank").Values(); !slices.Equal(got, []any{0.0, 1.0, 0.25, 0.25, 0.75}) {
+		t.Errorf("percent_rank: got %v", got)
+	}
+
+	if err := df.Window([]string{"missing"}, nil, []WindowFunction{RowNumber()}).Err(); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", err)
+	}
+	if err := df.Window(nil, nil, []WindowFunction{CumSum("customer")}).Err(); !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", err)
+	}
+
+	// Partition values containing the key separator are different partitions
+	separated := NewDataFrame(
+		series.NewStringSeries("a", []string{"a|b", "a"}),
+		series.NewStringSeries("b", []string{"c", "b|c"}),
+	).Window([]string{"a", "b"}, nil, []WindowFunction{RowNumber()})
+	if got := separated.GetSeries("row_number").Values(); !slices.Equal(got, []any{1, 1}) {
+		t.Errorf("Expected row numbers [1 1], got %v", got)
+	}
+}
+
+func TestParallelism(t *testing.T) {
+	// Tests that parallel operations give the same results as sequential ones
+	ids := make([]int, 1001)
+	for i := range ids {
+		ids[i] = i
+	}
+	df := NewDataFrame(series.NewIntSeries("id", ids))
+
+	run := func(n int) *DataFrame {
+		return df.SetParallelism(n).
+			ApplyIndex("double", func(values ...any) any { return values[0].(int) * 2 }, "id").
+			FilterMap(func(row map[string]any) bool { return row["double"].(int)%3 == 0 }).
+			FilterIndex(func(values ...any) bool { return values[0].(int) > 10 }, "id")
+	}
+	sequential, parallel := run(1), run(7)
+	if parallel.Parallelism() != 7 {
+		t.Errorf("Expected the result to keep parallelism 7, got %d", parallel.Parallelism())
+	}
+	if !slices.Equal(sequential.GetSeries("double").Values(), parallel.GetSeries("double").Values()) || sequential.Height() != 330 {
+		t.Errorf("Parallel result differs from sequential: %d and %d rows", sequential.Height(), parallel.Height())
+	}
+
+	SetDefaultParallelism(4)
+	defer SetDefaultParallelism(1)
+	if df.SetParallelism(0).Parallelism() != 4 || df.SetParallelism(2).Parallelism() != 2 {
+		t.Errorf("Expected parallelism 4 and 2, got %d", df.Parallelism())
+	}
+
+	// The setting is kept by results and doesn't change the original DataFrame
+	if df.SetParallelism(3).DropRow(0).Parallelism() != 3 || df.Parallelism() != 4 {
+		t.Errorf("Expected parallelism 3 for the result and 4 for the original, got %d", df.Parallelism())
+	}
+}
+
+func TestJoin(t *testing.T) {
+	// Tests inner and left joins, suffixes and null keys
+	orders := FromRecords([]map[string]any{
+		{"id": 1, "customer": 1, "amount": 10},
+		{"id": 2, "customer": 2, "amount": 20},
+		{"id": 3, "customer": 1, "amount": 30},
+		{"id": 4, "amount": 40},
+	}, OptionsMap{"columns": []string{"id", "customer", "amount"}})
+	customers := FromRecords([]map[string]any{
+		{"customer": 1, "name": "Ann", "amount": 1},
+		{"customer": 3, "name": "Cid", "amount": 3},
+	}, OptionsMap{"columns": []string{"customer", "name", "amount"}})
+
+	inner := orders.Join(customers, []string{"customer"})
+	if !slices.Equal(inner.ColumnNames(), []string{"id", "customer", "amount", "name", "amount_right"}) {
+		t.Errorf("Unexpected columns %v", inner.ColumnNames())
+	}
+	if !slices.Equal(inner.GetSeries("id").Values(), []any{1, 3}) || !slices.Equal(inner.GetSeries("name").Values(), []any{"Ann", "Ann"}) {
+		t.Errorf("Unexpected inner join %v", recordsOf(inner))
+	}
+
+	left := orders.Join(customers, []string{"customer"}, OptionsMap{"how": "left"})
+	if !slices.Equal(left.GetSeries("name").Values(), []any{"Ann", nil, "Ann", nil}) {
+		t.Errorf("Unexpected left join %v", recordsOf(left))
+	}
+
+	if err := orders.Join(customers, []string{"missing"}).Err(); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", err)
+	}
+
+	// Keys containing the separator, nulls and the text of a null never match each other
+	a := NewDataFrame(
+		series.NewStringSeriesWithNulls("x", []string{"a|b", "<nil>", ""}, []bool{false, false, true}),
+		series.NewStringSeries("y", []string{"c", "z", "z"}),
+	)
+	b := NewDataFrame(
+		series.NewStringSeriesWithNulls("x", []string{"a", "", ""}, []bool{false, true, true}),
+		series.NewStringSeries("y", []string{"b|c", "z", "z"}),
+	)
+	if joined := a.Join(b, []string{"x", "y"}); joined.Err() != nil || joined.Height() != 0 {
+		t.Errorf("Expected no matches, got %v", recordsOf(joined))
+	}
+}
+
+func TestSort(t *testing.T) {
+	// Tests sorting by several columns, descending order and nulls
+	df := FromRecords([]map[string]any{
+		{"group": "b", "value": 2},
+		{"group": "a", "value": 3},
+		{"group": "b"},
+		{"group": "a", "value": 1},
+		{"group": "b", "value": 5},
+	})
+
+	sorted := df.Sort([]string{"group", "value"}, OptionsMap{"descending": []bool{false, true}})
+	if !slices.Equal(sorted.GetSeries("value").Values(), []any{3, 1, 5, 2, nil}) {
+		t.Errorf("Unexpected order %v", recordsOf(sorted))
+	}
+	if sorted.GetSeries("value").Type() != intType {
+		t.Errorf("Expected the column type to be kept")
+	}
+
+	sorted = df.Sort([]string{"value"}, OptionsMap{"descending": true})
+	if !slices.Equal(sorted.GetSeries("value").Values(), []any{5, 3, 2, 1, nil}) {
+		t.Errorf("Unexpected order %v", recordsOf(sorted))
+	}
+
+	if err := df.Sort([]string{"missing"}).Err(); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestChunkedStorage(t *testing.T) {
+	// Tests that Concat, AddRow and the CSV reader link chunks instead of copying columns
+	df1 := NewDataFrame(series.NewIntSeries("id", []int{1, 2, 3}))
+	df2 := NewDataFrame(series.NewIntSeriesWithNulls("id", []int{4, 0}, []bool{false, true}))
+	df, err := Concat(df1, df2, df1)
+	if err != nil {
+		t.Fatalf("Error concatenating: %v", err)
+	}
+
+	chunked, ok := df.GetSeries("id").(*series.ChunkedSeries)
+	if !ok || len(chunked.Chunks()) != 3 || chunked.Type() != reflect.TypeOf(0) {
+		t.Fatalf("Expected an int column with 3 chunks, got %T", df.GetSeries("id"))
+	}
+	if !slices.Equal(chunked.Values(), []any{1, 2, 3, 4, nil, 1, 2, 3}) || !chunked.IsNull(4) {
+		t.Errorf("Expected [1 2 3 4 <nil> 1 2 3], got %v", chunked.Values())
+	}
+
+	// Views and iterators cross chunk boundaries
+	if view := series.Slice(chunked, 2, 6); !slices.Equal(view.Values(), []any{3, 4, nil, 1}) {
+		t.Errorf("Expected view [3 4 <nil> 1], got %v", view.Values())
+	}
+	count := 0
+	for i, v := range chunked.All() {
+		if v != chunked.Get(i) {
+			t.Errorf("Iterator yields %v at %d, expected %v", v, i, chunked.Get(i))
+		}
+		count++
+	}
+	if count != 8 {
+		t.Errorf("Expected the iterator to yield 8 values, got %d", count)
+	}
+
+	// Dropping rows only changes the affected chunks
+	if dropped := df.DropRows(2, 3, 7); !slices.Equal(dropped.GetSeries("id").Values(), []any{1, 2, nil, 1, 2}) {
+		t.Errorf("Expected [1 2 <nil> 1 2] after dropping rows, got %v", dropped.GetSeries("id").Values())
+	}
+	if compact, ok := chunked.Copy(true).(*series.IntSeries); !ok || !slices.Equal(compact.Values(), chunked.Values()) {
+		t.Errorf("Expected a deep copy to be a single IntSeries, got %T", chunked.Copy(true))
+	}
+
+	// Rows are appended to the last chunk and keep the column type
+	appended := df.AddRow([]any{9}).AddRow([]any{nil}).GetSeries("id")
+	if appended.Type() != reflect.TypeOf(0) || appended.Get(8) != 9 || !appended.IsNull(9) {
+		t.Errorf("Expected an int column ending with 9 and a null, got %v", appended.Values())
+	}
+	if chunks := appended.(*series.ChunkedSeries).Chunks(); len(chunks) != 3 || chunks[2].Len() != 5 {
+		t.Errorf("Expected the rows in the last chunk, got %d chunks", len(chunks))
+	}
+
+	// The CSV reader creates a chunk for each series.DefaultChunkSize rows
+	var csv strings.Builder
+	csv.WriteString("n\n")
+	for i := range series.DefaultChunkSize + 10 {
+		csv.WriteString(strconv.Itoa(i) + "\n")
+	}
+	read, err := Read().FromString(csv.String()).Option("header", true).Option("inferdatatypes", true).Load()
+	if err != nil {
+		t.Fatalf("Error reading CSV: %v", err)
+	}
+	column, ok := read.GetSeries("n").(*series.ChunkedSeries)
+	if !ok || len(column.Chunks()) != 2 || column.Get(series.DefaultChunkSize) != series.DefaultChunkSize {
+		t.Errorf("Expected an int column with 2 chunks, got %T", read.GetSeries("n"))
+	}
+
+	// Reading with a Schema creates chunks too
+	schema, _ := NewSchema(Field{Name: "n", Type: "int"})
+	read, err = Read().FromString(csv.String()).Option("header", true).Schema(schema).Load()
+	if err != nil {
+		t.Fatalf("Error reading CSV with a schema: %v", err)
+	}
+	if column, ok := read.GetSeries("n").(*series.ChunkedSeries); !ok || len(column.Chunks()) != 2 || column.Type() != reflect.TypeOf(0) {
+		t.Errorf("Expected an int column with 2 chunks, got %T", read.GetSeries("n"))
+	}
+}
+
+func TestMemoryUsageDeep(t *testing.T) {
+	// Tests the bytes counted for each column, with and without deep
+	df := NewDataFrame(
+		series.NewIntSeriesWithNulls("id", []int{1, 2, 0}, []bool{false, false, true}),
+		series.NewStringSeries("code", []string{"a", "a", "bb"}),
+		series.NewGenericSeries("any", []any{1, "x", nil}),
+	)
+
+	shallow := df.MemoryUsage(false)
+	if !slices.Equal(shallow.GetSeries("column").Values(), []any{"id", "code", "any"}) || shallow.Width() != 3 {
+		t.Fatalf("Expected a row for each column, got %v", shallow.ColumnNames())
+	}
+	if !slices.Equal(shallow.GetSeries("bytes").Values(), []any{27, 48, 48}) {
+		t.Errorf("Expected bytes [27 48 48], got %v", shallow.GetSeries("bytes").Values())
+	}
+
+	// Deep counts string payloads and boxed values
+	deep := df.MemoryUsage(true)
+	if !slices.Equal(deep.GetSeries("bytes").Values(), []any{27, 52, 73}) {
+		t.Errorf("Expected bytes [27 52 73], got %v", deep.GetSeries("bytes").Values())
+	}
+	if !slices.Equal(deep.GetSeries("unique").Values(), []any{2, 2, 2}) {
+		t.Errorf("Expected unique [2 2 2], got %v", deep.GetSeries("unique").Values())
+	}
+	if deep.GetSeries("dictionary_bytes").Get(1) != 2*16+3+3*4 {
+		t.Errorf("Expected 47 dictionary bytes for code, got %v", deep.GetSeries("dictionary_bytes").Get(1))
+	}
+}
+
+func TestStream(t *testing.T) {
+	// Tests reading a CSV in chunks with a schema inferred from the first rows
+	content := "id,score,name\n11,1.5,a\n12,2.5,b\n13,,c\n14,4.5,d\n15,x,e"
+	reader := func() *DataFrameReader {
+		return Read().FromString(content).Option("header", true).Option("inferdatatypes", true).Option("inferrows", 2)
+	}
+
+	heights := []int{}
+	var err error
+	for chunk, chunkErr := range reader().Stream(2) {
+		if chunkErr != nil {
+			err = chunkErr
+			break
+		}
+		heights = append(heights, chunk.Height())
+		for i, field := range chunk.Schema().Fields {
+			if expected := []string{"int", "float64", "string"}[i]; field.Type != expected {
+				t.Errorf("Expected %s to be %s in every chunk, got %s", field.Name, expected, field.Type)
+			}
+		}
+	}
+	if !slices.Equal(heights, []int{2, 2}) || !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Expected two chunks and then ErrSchemaMismatch, got %v and %v", heights, err)
+	}
+
+	// With coerce the value that doesn't match becomes a null
+	rows := 0
+	for chunk, err := range reader().Option("coerce", true).Stream(2) {
+		if err != nil {
+			t.Fatalf("Error streaming: %v", err)
+		}
+		rows += chunk.Height()
+		if chunk.Height() == 1 && !chunk.GetSeries("score").IsNull(0) {
+			t.Errorf("Expected the last score to be null, got %v", chunk.GetSeries("score").Get(0))
+		}
+	}
+	if rows != 5 {
+		t.Errorf("Expected 5 rows, got %d", rows)
+	}
+
+	// Stopping early and selecting columns
+	for chunk, err := range reader().Select("name").Stream(3) {
+		if err != nil || !slices.Equal(chunk.GetSeries("name").Values(), []any{"a", "b", "c"}) {
+			t.Errorf("Expected names [a b c], got %v (%v)", chunk.GetSeries("name").Values(), err)
+		}
+		break
+	}
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"teddy/dataframe/series"
@@ -57,10 +58,81 @@ func (dfr *DataFrameReader) Option(key string, value any) *DataFrameReader {
 		dfr.options.SetHeader(value.(bool))
 	case "inferdatatypes":
 		dfr.options.SetInferDataTypes(value.(bool))
+	case "inferrows":
+		dfr.options.SetInferRows(value.(int))
+	case "coerce":
+		dfr.options.SetCoerce(value.(bool))
 	}
 	return dfr
 }
 
+// Schema sets the Schema of the data.
+//
+// CSV columns are parsed directly into the declared types and parquet columns
+// are converted to them. Columns are matched by name when there is a header,
+// otherwise by position. Columns that are not in the Schema are dropped.
+func (dfr *DataFrameReader) Schema(schema *Schema) *DataFrameReader {
+	dfr.options.SetSchema(schema)
+	return dfr
+}
+
+// Select sets the columns to read, in order. Other columns are skipped while reading.
+func (dfr *DataFrameReader) Select(columns ...string) *DataFrameReader {
+	dfr.options.SetColumns(columns)
+	return dfr
+}
+
+// ColumnNames returns the names of the columns of the data source without reading the data.
+//
+// For CSV without a header the names are "Column 0", "Column 1" and so on.
+// Columns set with Select are not taken into account.
+func (dfr *DataFrameReader) ColumnNames() ([]string, error) {
+	fileType := dfr.fileType
+	if fileType == "" {
+		fileType = "csv"
+		if dfr.stringValue == "" {
+			fileType = detectFileType(dfr.filePath)
+		}
+	}
+
+	if fileType == "parquet" {
+		return readParquetColumnNames(dfr.filePath)
+	}
+	if fileType != "csv" {
+		return nil, fmt.Errorf("Unsupported file type: %s", fileType)
+	}
+
+	var source io.Reader = strings.NewReader(dfr.stringValue)
+	if dfr.stringValue == "" {
+		file, err := os.Open(dfr.filePath)
+		if err != nil {
+			return nil, fmt.Errorf("Error opening file: %s, %w", dfr.filePath, err)
+		}
+		defer file.Close()
+		source = file
+	}
+
+	csvReader := csv.NewReader(bufio.NewReader(source))
+	csvReader.Comma = dfr.options.GetDelimiter()
+	csvReader.TrimLeadingSpace = dfr.options.GetTrimLeadingSpace()
+	record, err := csvReader.Read()
+	if err == io.EOF {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading CSV data: %w", err)
+	}
+
+	if dfr.options.GetHeader() {
+		return record, nil
+	}
+	names := make([]string, len(record))
+	for i := range names {
+		names[i] = fmt.Sprintf("Column %d", i)
+	}
+	return names, nil
+}
+
 // Load reads the data source and returns a DataFrame
 func (dfr *DataFrameReader) Load() (*DataFrame, error) {
 	// Validate and standardize options
@@ -107,10 +179,14 @@ func (dfr *DataFrameReader) Load() (*DataFrame, error) {
 		}
 		return df, nil
 	case "parquet":
-		df, err := ReadParquet(dfr.filePath)
+		df, err := ReadParquet(dfr.filePath, OptionsMap{"columns": optionsStandard.GetColumns()})
 		if err != nil {
 			return nil, fmt.Errorf("Error reading Parquet file: %w", err)
 		}
+		if schema := optionsStandard.GetSchema(); schema != nil {
+			df = df.CastTo(schema)
+			return df, df.Err()
+		}
 		return df, nil
 	default:
 		return nil, fmt.Errorf("Unsupported file type: %s", dfr.fileType)
@@ -164,7 +240,7 @@ func readCSV(reader io.Reader, options *Options) (*DataFrame, error) {
 
 	// Read all records at once
 	// This is faster than reading line by line, but uses more memory
-	// If memory becomes an issue, we can implement a chunked reading approach
+	// Stream reads the data in chunks instead
 	records, err := csvReader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("Error reading CSV data: %w", err)
@@ -196,11 +272,30 @@ func readCSV(reader io.Reader, options *Options) (*DataFrame, error) {
 		dataRows = records
 	}
 
+	// Find the columns to read
+	colIndexes := make([]int, len(headers))
+	for i := range colIndexes {
+		colIndexes[i] = i
+	}
+	if columns := options.GetColumns(); columns != nil {
+		colIndexes = make([]int, len(columns))
+		for i, column := range columns {
+			colIndexes[i] = slices.Index(headers, column)
+			if colIndexes[i] == -1 {
+				return nil, columnNotFound(column)
+			}
+		}
+	}
+
+	if schema := options.GetSchema(); schema != nil {
+		return readCSVWithSchema(headers, dataRows, schema, options.GetHeader(), options.GetColumns())
+	}
+
 	// Create DataFrame with appropriate series types
 	df := NewDataFrame()
 
 	// Process each column
-	for colIdx := range headers {
+	for _, colIdx := range colIndexes {
 		// Extract column values
 		colValues := make([]string, len(dataRows))
 		for rowIdx, row := range dataRows {
@@ -211,46 +306,82 @@ func readCSV(reader io.Reader, options *Options) (*DataFrame, error) {
 			}
 		}
 
-		// Detect and create appropriate series type
+		// Detect the series type on the whole column, defaulting to string
+		seriesType := "string"
 		if options.GetInferDataTypes() {
-			seriesType, err := inferType(colValues)
-			if err != nil {
-				// If type inference fails, default to string
-				df.AddSeries(series.NewStringSeries(headers[colIdx], colValues))
-				continue
+			if inferred, err := inferType(colValues); err == nil {
+				seriesType = inferred
 			}
+		}
+
+		s, err := parseCSVColumn(headers[colIdx], colValues, seriesType)
+		if err != nil {
+			// If a value can't be converted, default to string
+			s, _ = parseCSVColumn(headers[colIdx], colValues, "string")
+		}
+		df = df.AddSeries(s)
+	}
 
-			switch seriesType {
-			case "int":
-				intValues, err := convertToIntSlice(colValues)
-				if err != nil {
-					df.AddSeries(series.NewStringSeries(headers[colIdx], colValues))
-				} else {
-					df.AddSeries(series.NewIntSeries(headers[colIdx], intValues))
-				}
-			case "float":
-				floatValues, err := convertToFloatSlice(colValues)
-				if err != nil {
-					df.AddSeries(series.NewStringSeries(headers[colIdx], colValues))
-				} else {
-					df.AddSeries(series.NewFloat64Series(headers[colIdx], floatValues))
-				}
-			case "bool":
-				boolValues, err := convertToBoolSlice(colValues)
-				if err != nil {
-					df.AddSeries(series.NewStringSeries(headers[colIdx], colValues))
-				} else {
-					df.AddSeries(series.NewBoolSeries(headers[colIdx], boolValues))
-				}
-			default:
-				df.AddSeries(series.NewStringSeries(headers[colIdx], colValues))
+	return df, nil
+}
+
+// parseCSVColumn converts the values of a column to the series type in chunks of
+// series.DefaultChunkSize rows, which are linked into one Series
+func parseCSVColumn(name string, values []string, seriesType string) (series.SeriesInterface, error) {
+	chunks := []series.SeriesInterface{}
+	for start := 0; start == 0 || start < len(values); start += series.DefaultChunkSize {
+		part := values[start:min(start+series.DefaultChunkSize, len(values))]
+
+		var chunk series.SeriesInterface
+		switch seriesType {
+		case "int":
+			intValues, err := convertToIntSlice(part)
+			if err != nil {
+				return nil, err
 			}
-		} else {
-			// No type inference, use string series
-			df.AddSeries(series.NewStringSeries(headers[colIdx], colValues))
+			chunk = series.NewIntSeries(name, intValues)
+		case "float":
+			floatValues, err := convertToFloatSlice(part)
+			if err != nil {
+				return nil, err
+			}
+			chunk = series.NewFloat64Series(name, floatValues)
+		case "bool":
+			boolValues, err := convertToBoolSlice(part)
+			if err != nil {
+				return nil, err
+			}
+			chunk = series.NewBoolSeries(name, boolValues)
+		default:
+			chunk = series.NewStringSeries(name, part)
 		}
+		chunks = append(chunks, chunk)
 	}
+	return series.Link(name, chunks...), nil
+}
+
+// readCSVWithSchema parses the columns of the Schema into their declared types
+func readCSVWithSchema(headers []string, dataRows [][]string, schema *Schema, hasHeader bool, columns []string) (*DataFrame, error) {
+	df := NewDataFrame()
+	for i, field := range schema.Fields {
+		if columns != nil && !slices.Contains(columns, field.Name) {
+			continue
+		}
 
+		colIdx := i
+		if hasHeader {
+			colIdx = slices.Index(headers, field.Name)
+		}
+		if colIdx == -1 || colIdx >= len(headers) {
+			return nil, columnNotFound(field.Name)
+		}
+
+		s, err := parseColumn(field, columnValues(dataRows, colIdx), false)
+		if err != nil {
+			return nil, err
+		}
+		df = df.AddSeries(s)
+	}
 	return df, nil
 }
 
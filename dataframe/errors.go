@@ -0,0 +1,51 @@
+package dataframe
+
+import (
+	"errors"
+	"fmt"
+	"teddy/dataframe/series"
+)
+
+var (
+	// ErrColumnNotFound is returned when a selected column doesn't exist
+	ErrColumnNotFound = errors.New("column not found")
+
+	// ErrLengthMismatch is returned when a Series or row doesn't match the size of the DataFrame
+	ErrLengthMismatch = errors.New("length mismatch")
+
+	// ErrTypeConversion is returned when the values of a column can't be converted to a type
+	ErrTypeConversion = series.ErrTypeConversion
+
+	// ErrSchemaMismatch is returned when a chunk of a stream doesn't match the schema of the first chunk
+	ErrSchemaMismatch = errors.New("schema mismatch")
+)
+
+// Err returns the first error that occurred while building the DataFrame, or nil.
+//
+// Operations on a DataFrame with an error do nothing and return the DataFrame,
+// so a chain of operations can be checked once at the end.
+func (df *DataFrame) Err() error {
+	return df.err
+}
+
+// withError returns the result of an operation that failed with err.
+// The first error of the DataFrame is kept.
+func (df *DataFrame) withError(err error) *DataFrame {
+	result := df.target()
+	if result.err == nil {
+		result.err = err
+	}
+	return result
+}
+
+// WithError returns the result of an operation that failed with err, as withError does for
+// the operations of this package, so other packages can report errors through Err too.
+// The first error of the DataFrame is kept.
+func (df *DataFrame) WithError(err error) *DataFrame {
+	return df.withError(err)
+}
+
+// columnNotFound returns an ErrColumnNotFound error listing the missing columns
+func columnNotFound(columns ...string) error {
+	return fmt.Errorf("%w: %s", ErrColumnNotFound, SprintfStringSlice(columns))
+}
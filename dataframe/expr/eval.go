@@ -0,0 +1,444 @@
+package expr
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"strings"
+	"teddy/dataframe/internal/compare"
+	"teddy/dataframe/series"
+	"unicode/utf8"
+)
+
+// Evaluate computes the expression over the columns, which all have height rows.
+//
+// The result is named by Name. Nulls propagate through operations, so the result
+// is null where an operand is null.
+// Returns an error if a column doesn't exist or an operand has the wrong type.
+func (e *Expr) Evaluate(columns []series.SeriesInterface, height int) (series.SeriesInterface, error) {
+	s, err := e.evaluate(columns, height)
+	if err != nil {
+		return nil, err
+	}
+	return s.Rename(e.Name()), nil
+}
+
+func (e *Expr) evaluate(columns []series.SeriesInterface, height int) (series.SeriesInterface, error) {
+	switch e.op {
+	case "col":
+		for _, s := range columns {
+			if s.Name() == e.name {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("Column not found: \"%s\"", e.name)
+	case "lit":
+		values := make([]any, height)
+		for i := range values {
+			values[i] = e.value
+		}
+		return series.NewSeries("literal", values), nil
+	}
+
+	args := make([]series.SeriesInterface, len(e.args))
+	for i, arg := range e.args {
+		s, err := arg.evaluate(columns, height)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = s
+	}
+
+	switch e.op {
+	case "add", "sub", "mul", "div":
+		return arithmetic(e.op, args[0], args[1])
+	case "eq", "ne", "gt", "ge", "lt", "le":
+		return comparison(e.op, args[0], args[1])
+	case "and", "or", "not":
+		return logical(e.op, args)
+	case "is_null", "is_not_null":
+		values := make([]bool, height)
+		for i := range values {
+			values[i] = args[0].IsNull(i) == (e.op == "is_null")
+		}
+		return series.NewBoolSeries(args[0].Name(), values), nil
+	case "contains", "starts_with", "ends_with", "upper", "lower", "trim", "len":
+		return stringOperation(e.op, args[0], e.value)
+	case "when":
+		return conditional(args)
+	case "abs", "round":
+		return rounding(e.op, args[0], e.value)
+	case "coalesce":
+		return coalesce(args), nil
+	}
+	return nil, fmt.Errorf("Unknown operation \"%s\"", e.op)
+}
+
+// arithmetic applies an arithmetic operation to two numeric series.
+// Two int series give an int series, except for division which always gives float64.
+func arithmetic(op string, a, b series.SeriesInterface) (series.SeriesInterface, error) {
+	aFloats, aInts, err := numbers(a)
+	if err != nil {
+		return nil, err
+	}
+	bFloats, bInts, err := numbers(b)
+	if err != nil {
+		return nil, err
+	}
+	nulls := combineNulls(a, b)
+
+	if aInts != nil && bInts != nil && op != "div" {
+		values := make([]int, len(aInts))
+		for i := range values {
+			switch op {
+			case "add":
+				values[i] = aInts[i] + bInts[i]
+			case "sub":
+				values[i] = aInts[i] - bInts[i]
+			case "mul":
+				values[i] = aInts[i] * bInts[i]
+			}
+		}
+		return series.NewIntSeriesWithNulls(a.Name(), values, nulls), nil
+	}
+
+	aFloats, bFloats = toFloats(aFloats, aInts), toFloats(bFloats, bInts)
+	values := make([]float64, len(aFloats))
+	for i := range values {
+		switch op {
+		case "add":
+			values[i] = aFloats[i] + bFloats[i]
+		case "sub":
+			values[i] = aFloats[i] - bFloats[i]
+		case "mul":
+			values[i] = aFloats[i] * bFloats[i]
+		case "div":
+			values[i] = aFloats[i] / bFloats[i]
+		}
+	}
+	return series.NewFloat64SeriesWithNulls(a.Name(), values, nulls), nil
+}
+
+// comparison compares two series row by row.
+// Numbers are compared numerically, strings, bools and times with values of the same type.
+func comparison(op string, a, b series.SeriesInterface) (series.SeriesInterface, error) {
+	nulls := combineNulls(a, b)
+	results := make([]int, a.Len())
+
+	aFloats, aInts, aErr := numbers(a)
+	bFloats, bInts, bErr := numbers(b)
+	switch {
+	case aErr == nil && bErr == nil && aInts != nil && bInts != nil:
+		for i := range results {
+			results[i] = cmp.Compare(aInts[i], bInts[i])
+		}
+	case aErr == nil && bErr == nil:
+		aFloats, bFloats = toFloats(aFloats, aInts), toFloats(bFloats, bInts)
+		for i := range results {
+			results[i] = cmp.Compare(aFloats[i], bFloats[i])
+		}
+	default:
+		for i := range results {
+			if nulls != nil && nulls[i] {
+				continue
+			}
+			result, ok := compare.Values(a.Get(i), b.Get(i))
+			if !ok {
+				return nil, fmt.Errorf("%w: cannot compare %T with %T", series.ErrTypeConversion, a.Get(i), b.Get(i))
+			}
+			results[i] = result
+		}
+	}
+
+	values := make([]bool, len(results))
+	for i, result := range results {
+		switch op {
+		case "eq":
+			values[i] = result == 0
+		case "ne":
+			values[i] = result != 0
+		case "gt":
+			values[i] = result > 0
+		case "ge":
+			values[i] = result >= 0
+		case "lt":
+			values[i] = result < 0
+		case "le":
+			values[i] = result <= 0
+		}
+	}
+	return series.NewBoolSeriesWithNulls(a.Name(), values, nulls), nil
+}
+
+// logical applies and, or or not to bool series
+func logical(op string, args []series.SeriesInterface) (series.SeriesInterface, error) {
+	operands := make([][]bool, len(args))
+	for i, s := range args {
+		values, err := bools(s)
+		if err != nil {
+			return nil, err
+		}
+		operands[i] = values
+	}
+
+	values := make([]bool, len(operands[0]))
+	for i := range values {
+		switch op {
+		case "and":
+			values[i] = operands[0][i] && operands[1][i]
+		case "or":
+			values[i] = operands[0][i] || operands[1][i]
+		case "not":
+			values[i] = !operands[0][i]
+		}
+	}
+	return series.NewBoolSeriesWithNulls(args[0].Name(), values, combineNulls(args...)), nil
+}
+
+// stringOperation applies a string operation, with an optional string parameter, to a string series
+func stringOperation(op string, s series.SeriesInterface, parameter any) (series.SeriesInterface, error) {
+	values, err := stringValues(s)
+	if err != nil {
+		return nil, err
+	}
+	nulls := combineNulls(s)
+
+	switch op {
+	case "contains", "starts_with", "ends_with":
+		matches := make([]bool, len(values))
+		for i, value := range values {
+			switch op {
+			case "contains":
+				matches[i] = strings.Contains(value, parameter.(string))
+			case "starts_with":
+				matches[i] = strings.HasPrefix(value, parameter.(string))
+			case "ends_with":
+				matches[i] = strings.HasSuffix(value, parameter.(string))
+			}
+		}
+		return series.NewBoolSeriesWithNulls(s.Name(), matches, nulls), nil
+	case "len":
+		lengths := make([]int, len(values))
+		for i, value := range values {
+			lengths[i] = utf8.RuneCountInString(value)
+		}
+		return series.NewIntSeriesWithNulls(s.Name(), lengths, nulls), nil
+	}
+
+	mapped := make([]string, len(values))
+	for i, value := range values {
+		switch op {
+		case "upper":
+			mapped[i] = strings.ToUpper(value)
+		case "lower":
+			mapped[i] = strings.ToLower(value)
+		case "trim":
+			mapped[i] = strings.TrimSpace(value)
+		}
+	}
+	return series.NewStringSeriesWithNulls(s.Name(), mapped, nulls), nil
+}
+
+// conditional picks each row from the value of the first true condition, or the last series otherwise.
+// The arguments alternate between conditions and values, with the otherwise value last.
+func conditional(args []series.SeriesInterface) (series.SeriesInterface, error) {
+	conditions := [][]bool{}
+	for i := 0; i+1 < len(args); i += 2 {
+		values, err := bools(args[i])
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, values)
+	}
+
+	otherwise := args[len(args)-1]
+	values := make([]any, otherwise.Len())
+	for row := range values {
+		chosen := otherwise
+		for i, condition := range conditions {
+			if condition[row] && !args[2*i].IsNull(row) {
+				chosen = args[2*i+1]
+				break
+			}
+		}
+		values[row] = chosen.Get(row)
+	}
+	return series.NewSeries(args[1].Name(), promoteInts(values)), nil
+}
+
+// coalesce picks each row from the first series that is not null there
+func coalesce(args []series.SeriesInterface) series.SeriesInterface {
+	values := make([]any, args[0].Len())
+	for row := range values {
+		for _, arg := range args {
+			if !arg.IsNull(row) {
+				values[row] = arg.Get(row)
+				break
+			}
+		}
+	}
+	return series.NewSeries(args[0].Name(), promoteInts(values))
+}
+
+// promoteInts converts ints to floats when the values mix both
+func promoteInts(values []any) []any {
+	hasInt, hasFloat := false, false
+	for _, value := range values {
+		switch value.(type) {
+		case int:
+			hasInt = true
+		case float64:
+			hasFloat = true
+		}
+	}
+	if hasInt && hasFloat {
+		for i, value := range values {
+			if v, ok := value.(int); ok {
+				values[i] = float64(v)
+			}
+		}
+	}
+	return values
+}
+
+// rounding applies abs or round to a numeric series, keeping its type
+func rounding(op string, s series.SeriesInterface, decimals any) (series.SeriesInterface, error) {
+	floats, ints, err := numbers(s)
+	if err != nil {
+		return nil, err
+	}
+	nulls := combineNulls(s)
+
+	if ints != nil {
+		values := make([]int, len(ints))
+		for i, v := range ints {
+			values[i] = v
+			if op == "abs" && v < 0 {
+				values[i] = -v
+			}
+		}
+		return series.NewIntSeriesWithNulls(s.Name(), values, nulls), nil
+	}
+
+	values := make([]float64, len(floats))
+	for i, v := range floats {
+		if op == "abs" {
+			values[i] = math.Abs(v)
+		} else {
+			scale := math.Pow(10, float64(decimals.(int)))
+			values[i] = math.Round(v*scale) / scale
+		}
+	}
+	return series.NewFloat64SeriesWithNulls(s.Name(), values, nulls), nil
+}
+
+// numbers returns the values of a numeric series as ints for an IntSeries, otherwise as floats.
+// Null values are zero.
+func numbers(s series.SeriesInterface) ([]float64, []int, error) {
+	switch typed := s.(type) {
+	case *series.ChunkedSeries:
+		return numbers(typed.Compact())
+	case *series.IntSeries:
+		ints := make([]int, s.Len())
+		for i := range ints {
+			ints[i] = typed.Value(i)
+		}
+		return nil, ints, nil
+	case *series.Float64Series:
+		floats := make([]float64, s.Len())
+		for i := range floats {
+			floats[i] = typed.Value(i)
+		}
+		return floats, nil, nil
+	}
+
+	floats := make([]float64, s.Len())
+	for i := range floats {
+		if s.IsNull(i) {
+			continue
+		}
+		switch v := s.Get(i).(type) {
+		case int:
+			floats[i] = float64(v)
+		case float64:
+			floats[i] = v
+		default:
+			return nil, nil, fmt.Errorf("%w: column \"%s\" is not numeric", series.ErrTypeConversion, s.Name())
+		}
+	}
+	return floats, nil, nil
+}
+
+// toFloats returns the floats, or the ints converted to floats if floats is nil
+func toFloats(floats []float64, ints []int) []float64 {
+	if floats != nil {
+		return floats
+	}
+	floats = make([]float64, len(ints))
+	for i, v := range ints {
+		floats[i] = float64(v)
+	}
+	return floats
+}
+
+// bools returns the values of a bool series. Null values are false.
+func bools(s series.SeriesInterface) ([]bool, error) {
+	values := make([]bool, s.Len())
+	if typed, ok := s.(*series.BoolSeries); ok {
+		for i := range values {
+			values[i] = typed.Value(i)
+		}
+		return values, nil
+	}
+
+	for i := range values {
+		if s.IsNull(i) {
+			continue
+		}
+		v, ok := s.Get(i).(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: column \"%s\" is not bool", series.ErrTypeConversion, s.Name())
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// stringValues returns the values of a string series. Null values are empty.
+func stringValues(s series.SeriesInterface) ([]string, error) {
+	values := make([]string, s.Len())
+	if typed, ok := s.(*series.StringSeries); ok {
+		for i := range values {
+			values[i] = typed.Value(i)
+		}
+		return values, nil
+	}
+
+	for i := range values {
+		if s.IsNull(i) {
+			continue
+		}
+		v, ok := s.Get(i).(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: column \"%s\" is not string", series.ErrTypeConversion, s.Name())
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// combineNulls returns a mask that is true where any of the series is null, or nil if none are
+func combineNulls(args ...series.SeriesInterface) []bool {
+	var nulls []bool
+	for _, s := range args {
+		for i := 0; i < s.Len(); i++ {
+			if s.IsNull(i) {
+				if nulls == nil {
+					nulls = make([]bool, s.Len())
+				}
+				nulls[i] = true
+			}
+		}
+	}
+	return nulls
+}
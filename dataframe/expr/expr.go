@@ -0,0 +1,250 @@
+package expr
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Expr is an expression over the columns of a DataFrame.
+//
+// Expressions are trees of operations that can be inspected and reused,
+// and are evaluated a column at a time against the typed series.
+// Methods that take an operand accept an *Expr or a value, which is used as a literal.
+type Expr struct {
+	op    string
+	name  string
+	value any
+	args  []*Expr
+	alias string
+}
+
+// Col returns an expression for the column with the given name
+func Col(name string) *Expr {
+	return &Expr{op: "col", name: name}
+}
+
+// Lit returns an expression for a constant value
+func Lit(value any) *Expr {
+	return &Expr{op: "lit", value: value}
+}
+
+// toExpr returns the operand as an expression, wrapping values with Lit
+func toExpr(operand any) *Expr {
+	if e, ok := operand.(*Expr); ok {
+		return e
+	}
+	return Lit(operand)
+}
+
+func (e *Expr) binary(op string, other any) *Expr {
+	return &Expr{op: op, args: []*Expr{e, toExpr(other)}}
+}
+
+func (e *Expr) unary(op string, value any) *Expr {
+	return &Expr{op: op, value: value, args: []*Expr{e}}
+}
+
+// Op returns the name of the operation, such as "col", "lit", "add" or "when"
+func (e *Expr) Op() string {
+	return e.op
+}
+
+// Args returns the operands of the expression
+func (e *Expr) Args() []*Expr {
+	return e.args
+}
+
+// Value returns the value of a literal, or the parameter of a string operation
+func (e *Expr) Value() any {
+	return e.value
+}
+
+// Name returns the name of the resulting column.
+//
+// This is the alias if one is set, the column name for Col, "literal" for Lit,
+// the name of the first value for When, and otherwise the name of the first operand.
+func (e *Expr) Name() string {
+	switch {
+	case e.alias != "":
+		return e.alias
+	case e.op == "col":
+		return e.name
+	case e.op == "lit":
+		return "literal"
+	case e.op == "when":
+		return e.args[1].Name()
+	}
+	return e.args[0].Name()
+}
+
+// Alias returns the expression with a new name for the resulting column
+func (e *Expr) Alias(name string) *Expr {
+	aliased := *e
+	aliased.alias = name
+	return &aliased
+}
+
+// Columns returns the names of the columns the expression reads, in order of first use
+func (e *Expr) Columns() []string {
+	columns := []string{}
+	seen := make(map[string]bool)
+	var walk func(e *Expr)
+	walk = func(e *Expr) {
+		if e.op == "col" && !seen[e.name] {
+			seen[e.name] = true
+			columns = append(columns, e.name)
+		}
+		for _, arg := range e.args {
+			walk(arg)
+		}
+	}
+	walk(e)
+	return columns
+}
+
+// String returns a readable representation of the expression
+func (e *Expr) String() string {
+	var s string
+	switch e.op {
+	case "col":
+		s = fmt.Sprintf("col(%q)", e.name)
+	case "lit":
+		s = fmt.Sprintf("lit(%#v)", e.value)
+	case "when":
+		parts := []string{}
+		for i := 0; i+1 < len(e.args); i += 2 {
+			parts = append(parts, fmt.Sprintf("when(%s).then(%s)", e.args[i], e.args[i+1]))
+		}
+		s = strings.Join(parts, ".") + fmt.Sprintf(".otherwise(%s)", e.args[len(e.args)-1])
+	default:
+		args := make([]string, len(e.args))
+		for i, arg := range e.args {
+			args[i] = arg.String()
+		}
+		if e.value != nil {
+			args = append(args, fmt.Sprintf("%#v", e.value))
+		}
+		s = fmt.Sprintf("%s(%s)", e.op, strings.Join(args, ", "))
+	}
+	if e.alias != "" {
+		s += fmt.Sprintf(".alias(%q)", e.alias)
+	}
+	return s
+}
+
+// Add returns the sum of the expression and other
+func (e *Expr) Add(other any) *Expr { return e.binary("add", other) }
+
+// Sub returns the difference of the expression and other
+func (e *Expr) Sub(other any) *Expr { return e.binary("sub", other) }
+
+// Mul returns the product of the expression and other
+func (e *Expr) Mul(other any) *Expr { return e.binary("mul", other) }
+
+// Div returns the expression divided by other, always as a float64
+func (e *Expr) Div(other any) *Expr { return e.binary("div", other) }
+
+// Eq returns true where the expression is equal to other
+func (e *Expr) Eq(other any) *Expr { return e.binary("eq", other) }
+
+// Ne returns true where the expression is not equal to other
+func (e *Expr) Ne(other any) *Expr { return e.binary("ne", other) }
+
+// Gt returns true where the expression is greater than other
+func (e *Expr) Gt(other any) *Expr { return e.binary("gt", other) }
+
+// Ge returns true where the expression is greater than or equal to other
+func (e *Expr) Ge(other any) *Expr { return e.binary("ge", other) }
+
+// Lt returns true where the expression is less than other
+func (e *Expr) Lt(other any) *Expr { return e.binary("lt", other) }
+
+// Le returns true where the expression is less than or equal to other
+func (e *Expr) Le(other any) *Expr { return e.binary("le", other) }
+
+// And returns the logical AND of the expression and other
+func (e *Expr) And(other any) *Expr { return e.binary("and", other) }
+
+// Or returns the logical OR of the expression and other
+func (e *Expr) Or(other any) *Expr { return e.binary("or", other) }
+
+// Not returns the logical NOT of the expression
+func (e *Expr) Not() *Expr { return e.unary("not", nil) }
+
+// IsNull returns true where the expression is null
+func (e *Expr) IsNull() *Expr { return e.unary("is_null", nil) }
+
+// IsNotNull returns true where the expression is not null
+func (e *Expr) IsNotNull() *Expr { return e.unary("is_not_null", nil) }
+
+// Contains returns true where the string contains the substring
+func (e *Expr) Contains(substring string) *Expr { return e.unary("contains", substring) }
+
+// StartsWith returns true where the string starts with the prefix
+func (e *Expr) StartsWith(prefix string) *Expr { return e.unary("starts_with", prefix) }
+
+// EndsWith returns true where the string ends with the suffix
+func (e *Expr) EndsWith(suffix string) *Expr { return e.unary("ends_with", suffix) }
+
+// Upper returns the string in upper case
+func (e *Expr) Upper() *Expr { return e.unary("upper", nil) }
+
+// Lower returns the string in lower case
+func (e *Expr) Lower() *Expr { return e.unary("lower", nil) }
+
+// Trim returns the string without leading and trailing white space
+func (e *Expr) Trim() *Expr { return e.unary("trim", nil) }
+
+// Len returns the number of characters in the string
+func (e *Expr) Len() *Expr { return e.unary("len", nil) }
+
+// Abs returns the absolute value of the number
+func (e *Expr) Abs() *Expr { return e.unary("abs", nil) }
+
+// Round returns the number rounded to the given number of decimal places. Ints are unchanged.
+func (e *Expr) Round(decimals int) *Expr { return e.unary("round", decimals) }
+
+// Coalesce returns the first value that is not null, trying the expression and then the others in order
+func (e *Expr) Coalesce(others ...any) *Expr {
+	args := []*Expr{e}
+	for _, other := range others {
+		args = append(args, toExpr(other))
+	}
+	return &Expr{op: "coalesce", args: args}
+}
+
+// Conditional builds a When/Then/Otherwise expression.
+//
+// Each step returns a new value, so a Conditional can be shared and extended in several ways.
+type Conditional struct {
+	args []*Expr
+}
+
+// WhenClause is a condition of a Conditional waiting for its value.
+// It only has Then, so a condition can't be left without a value.
+type WhenClause struct {
+	args []*Expr
+}
+
+// When starts a conditional expression. The value of the first condition that is true is used.
+//
+//	expr.When(expr.Col("age").Lt(18)).Then("minor").Otherwise("adult")
+func When(condition any) *WhenClause {
+	return &WhenClause{args: []*Expr{toExpr(condition)}}
+}
+
+// Then sets the value used where the condition is true
+func (w *WhenClause) Then(value any) *Conditional {
+	return &Conditional{args: append(slices.Clone(w.args), toExpr(value))}
+}
+
+// When adds a condition checked where the previous conditions are false
+func (c *Conditional) When(condition any) *WhenClause {
+	return &WhenClause{args: append(slices.Clone(c.args), toExpr(condition))}
+}
+
+// Otherwise sets the value used where no condition is true and returns the expression
+func (c *Conditional) Otherwise(value any) *Expr {
+	return &Expr{op: "when", args: append(slices.Clone(c.args), toExpr(value))}
+}
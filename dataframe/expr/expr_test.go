@@ -0,0 +1,248 @@
+package expr_test
+
+import (
+	"errors"
+	"slices"
+	"teddy/dataframe/expr"
+	"teddy/dataframe/series"
+	"testing"
+)
+
+var columns = []series.SeriesInterface{
+	series.NewIntSeries("a", []int{10, 20, 30}),
+	series.NewFloat64Series("b", []float64{0.5, 2, 4}),
+	series.NewSeries("name", []any{"Jack", nil, "jill"}),
+}
+
+func TestArithmetic(t *testing.T) {
+	// Tests that int stays int, mixing with floats gives floats and division gives floats
+	tests := []struct {
+		e        *expr.Expr
+		expected []any
+	}{
+		{expr.Col("a").Add(1), []any{11, 21, 31}},
+		{expr.Col("a").Mul(expr.Col("b")), []any{5.0, 40.0, 120.0}},
+		{expr.Col("a").Sub(expr.Col("a")).Div(expr.Col("a")), []any{0.0, 0.0, 0.0}},
+		{expr.Col("a").Div(4), []any{2.5, 5.0, 7.5}},
+	}
+	for _, test := range tests {
+		s, err := test.e.Evaluate(columns, 3)
+		if err != nil {
+			t.Errorf("%s: %v", test.e, err)
+			continue
+		}
+		if !slices.Equal(s.Values(), test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.e, test.expected, s.Values())
+		}
+	}
+}
+
+func TestComparisonAndLogic(t *testing.T) {
+	// Tests comparisons, boolean operators and null propagation
+	tests := []struct {
+		e        *expr.Expr
+		expected []any
+	}{
+		{expr.Col("a").Gt(15), []any{false, true, true}},
+		{expr.Col("b").Le(expr.Col("a")), []any{true, true, true}},
+		{expr.Col("a").Gt(15).And(expr.Col("b").Lt(3)), []any{false, true, false}},
+		{expr.Col("a").Eq(10).Or(expr.Col("a").Eq(30)).Not(), []any{false, true, false}},
+		{expr.Col("name").Eq("Jack"), []any{true, nil, false}},
+		{expr.Col("name").IsNull(), []any{false, true, false}},
+	}
+	for _, test := range tests {
+		s, err := test.e.Evaluate(columns, 3)
+		if err != nil {
+			t.Errorf("%s: %v", test.e, err)
+			continue
+		}
+		if !slices.Equal(s.Values(), test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.e, test.expected, s.Values())
+		}
+	}
+}
+
+func TestStringOperations(t *testing.T) {
+	// Tests the string operations
+	tests := []struct {
+		e        *expr.Expr
+		expected []any
+	}{
+		{expr.Col("name").Upper(), []any{"JACK", nil, "JILL"}},
+		{expr.Col("name").StartsWith("J"), []any{true, nil, false}},
+		{expr.Col("name").Contains("il"), []any{false, nil, true}},
+		{expr.Col("name").Len(), []any{4, nil, 4}},
+	}
+	for _, test := range tests {
+		s, err := test.e.Evaluate(columns, 3)
+		if err != nil {
+			t.Errorf("%s: %v", test.e, err)
+			continue
+		}
+		if !slices.Equal(s.Values(), test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.e, test.expected, s.Values())
+		}
+	}
+}
+
+func TestWhenAndAlias(t *testing.T) {
+	// Tests conditional expressions, naming and inspection
+	e := expr.When(expr.Col("a").Lt(15)).Then(0).
+		When(expr.Col("a").Lt(25)).Then(expr.Col("b")).
+		Otherwise(expr.Col("a")).
+		Alias("c")
+
+	s, err := e.Evaluate(columns, 3)
+	if err != nil {
+		t.Fatalf("Error evaluating: %v", err)
+	}
+	if s.Name() != "c" || !slices.Equal(s.Values(), []any{0.0, 2.0, 30.0}) {
+		t.Errorf("Expected c = [0 2 30], got %s = %v", s.Name(), s.Values())
+	}
+	if !slices.Equal(e.Columns(), []string{"a", "b"}) {
+		t.Errorf("Expected columns [a b], got %v", e.Columns())
+	}
+
+	named := expr.Col("a").Mul(2)
+	if named.Name() != "a" || named.String() != `mul(col("a"), lit(2))` {
+		t.Errorf("Unexpected name %s or string %s", named.Name(), named)
+	}
+}
+
+func TestWhenBranches(t *testing.T) {
+	// Tests that each step of a conditional returns a new value, so a shared start can be extended twice
+	base := expr.When(expr.Col("a").Lt(15)).Then(0)
+	long := base.When(expr.Col("a").Lt(25)).Then(2).Otherwise(3)
+	base.When(expr.Col("a").Lt(35)).Then(4)
+	short := base.Otherwise(1)
+
+	for _, test := range []struct {
+		e        *expr.Expr
+		expected []any
+	}{
+		{short, []any{0, 1, 1}},
+		{long, []any{0, 2, 3}},
+	} {
+		s, err := test.e.Evaluate(columns, 3)
+		if err != nil {
+			t.Fatalf("Error evaluating %s: %v", test.e, err)
+		}
+		if !slices.Equal(s.Values(), test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.e, test.expected, s.Values())
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	// Tests that type errors are reported
+	if _, err := expr.Col("name").Add(1).Evaluate(columns, 3); !errors.Is(err, series.ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", err)
+	}
+	if _, err := expr.Col("a").Upper().Evaluate(columns, 3); !errors.Is(err, series.ErrTypeConversion) {
+		t.Errorf("Expected ErrTypeConversion, got %v", err)
+	}
+	if _, err := expr.Col("missing").Evaluate(columns, 3); err == nil {
+		t.Errorf("Expected an error for a missing column")
+	}
+}
+
+func TestParse(t *testing.T) {
+	// Tests that parsed formulas evaluate like the equivalent expressions
+	tests := []struct {
+		formula  string
+		expected []any
+	}{
+		{"a + 2 * -a", []any{-10, -20, -30}},
+		{"(a - 5) / a", []any{0.5, 0.75, 5.0 / 6}},
+		{"abs(b - 3) + round(b / 3, 2)", []any{2.67, 1.67, 2.33}},
+		{"coalesce(upper(name), 'none')", []any{"JACK", "none", "JILL"}},
+		{"len(`name`) * 1.5", []any{6.0, nil, 6.0}},
+	}
+	for _, test := range tests {
+		e, err := expr.Parse(test.formula)
+		if err != nil {
+			t.Errorf("%s: %v", test.formula, err)
+			continue
+		}
+		s, err := e.Evaluate(columns, 3)
+		if err != nil {
+			t.Errorf("%s: %v", test.formula, err)
+			continue
+		}
+		if !slices.Equal(s.Values(), test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.formula, test.expected, s.Values())
+		}
+	}
+
+	e, err := expr.ParseAssignment("c = a * 2")
+	if err != nil || e.Name() != "c" {
+		t.Errorf("Expected an expression named c, got %v, %v", e, err)
+	}
+}
+
+func TestParseQuotedNames(t *testing.T) {
+	// Tests that names quoted with backticks are columns even when they look like literals
+	for _, name := range []string{"null", "true", "False"} {
+		e, err := expr.Parse("`" + name + "`")
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !slices.Equal(e.Columns(), []string{name}) {
+			t.Errorf("%s: expected a column, got %s", name, e)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	// Tests that syntax errors report their position
+	tests := []struct {
+		formula string
+		pos     int
+	}{
+		{"a +", 3},
+		{"a + * b", 4},
+		{"abs(a", 5},
+		{"a b", 2},
+		{"sqrt(a)", 0},
+		{"round(a, b)", 0},
+		{"a % 2", 2},
+		{"'open", 0},
+	}
+	for _, test := range tests {
+		_, err := expr.Parse(test.formula)
+		var syntaxErr *expr.SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Pos != test.pos {
+			t.Errorf("%s: expected a syntax error at position %d, got %v", test.formula, test.pos, err)
+		}
+	}
+}
+
+func TestResultType(t *testing.T) {
+	// Tests type checking against column types
+	types := map[string]string{"a": "int", "b": "float64", "name": "string", "other": "any"}
+	tests := []struct {
+		formula  string
+		expected string
+	}{
+		{"a * 2", "int"},
+		{"a / 2", "float64"},
+		{"coalesce(a, b)", "float64"},
+		{"upper(name)", "string"},
+		{"other + 1", "float64"},
+		{"abs(a)", "int"},
+	}
+	for _, test := range tests {
+		e, _ := expr.Parse(test.formula)
+		if typ, err := e.ResultType(types); err != nil || typ != test.expected {
+			t.Errorf("%s: expected %s, got %s, %v", test.formula, test.expected, typ, err)
+		}
+	}
+
+	for _, formula := range []string{"name + 1", "abs(name)", "coalesce(name, a)", "upper(a)"} {
+		e, _ := expr.Parse(formula)
+		if _, err := e.ResultType(types); !errors.Is(err, series.ErrTypeConversion) {
+			t.Errorf("%s: expected ErrTypeConversion, got %v", formula, err)
+		}
+	}
+}
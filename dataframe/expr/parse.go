@@ -0,0 +1,260 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"teddy/dataframe/internal/lexer"
+)
+
+// SyntaxError is an error in a formula, at a position counted in characters from 0
+type SyntaxError = lexer.Error
+
+// Parse parses a formula such as
+//
+//	round((price - cost) / price * 100, 1)
+//
+// Formulas have column names, number, string ('...' or "..."), true, false and null literals,
+// + - * / and parentheses, and the functions abs(x), round(x) or round(x, decimals),
+// upper(x), lower(x), trim(x), len(x) and coalesce(x, y, ...).
+// Names with spaces or symbols can be quoted with backticks.
+// Syntax errors are returned as a *SyntaxError with the position.
+func Parse(formula string) (*Expr, error) {
+	p, err := newParser(formula)
+	if err != nil {
+		return nil, err
+	}
+	e, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return e, p.expectEnd()
+}
+
+// ParseAssignment parses a formula of the form "name = formula" and returns the formula aliased to the name
+func ParseAssignment(assignment string) (*Expr, error) {
+	p, err := newParser(assignment)
+	if err != nil {
+		return nil, err
+	}
+	name := p.next()
+	if name.Kind != lexer.Ident {
+		return nil, p.errorAt(name, "Expected a column name")
+	}
+	if t := p.next(); t.Text != "=" || t.Kind != lexer.Symbol {
+		return nil, p.errorAt(t, `Expected "="`)
+	}
+	e, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return e.Alias(name.Text), p.expectEnd()
+}
+
+// lex splits formulas into tokens
+var lex = &lexer.Lexer{Symbols: []string{"(", ")", "+", "-", "*", "/", ",", "="}}
+
+// parser is a recursive descent parser for formulas
+type parser struct {
+	tokens []lexer.Token
+	pos    int
+}
+
+// newParser splits the formula into tokens
+func newParser(formula string) (*parser, error) {
+	tokens, err := lex.Tokenize(formula)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() lexer.Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() lexer.Token {
+	t := p.tokens[p.pos]
+	if t.Kind != lexer.EOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next lexer.Token if it is one of the symbols
+func (p *parser) accept(symbols ...string) (string, bool) {
+	t := p.peek()
+	for _, symbol := range symbols {
+		if t.Kind == lexer.Symbol && t.Text == symbol {
+			p.pos++
+			return symbol, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) errorAt(t lexer.Token, message string) error {
+	found := strconv.Quote(t.Text)
+	if t.Kind == lexer.EOF {
+		found = "end of formula"
+	}
+	return &SyntaxError{Pos: t.Pos, Message: fmt.Sprintf("%s, found %s", message, found)}
+}
+
+func (p *parser) expectEnd() error {
+	if t := p.peek(); t.Kind != lexer.EOF {
+		return p.errorAt(t, "Expected an operator")
+	}
+	return nil
+}
+
+func (p *parser) parseAdditive() (*Expr, error) {
+	left, err := p.parseMultiplicative()
+	for err == nil {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			break
+		}
+		var right *Expr
+		if right, err = p.parseMultiplicative(); err != nil {
+			break
+		}
+		switch op {
+		case "+":
+			left = left.Add(right)
+		case "-":
+			left = left.Sub(right)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseMultiplicative() (*Expr, error) {
+	left, err := p.parseUnary()
+	for err == nil {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			break
+		}
+		var right *Expr
+		if right, err = p.parseUnary(); err != nil {
+			break
+		}
+		switch op {
+		case "*":
+			left = left.Mul(right)
+		case "/":
+			left = left.Div(right)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseUnary() (*Expr, error) {
+	if _, ok := p.accept("-"); !ok {
+		return p.parsePrimary()
+	}
+	if t := p.peek(); t.Kind == lexer.Number {
+		p.next()
+		return p.number(lexer.Token{Kind: lexer.Number, Text: "-" + t.Text, Pos: t.Pos})
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return operand.Mul(-1), nil
+}
+
+// number returns a literal for the number, an int unless it has a decimal point
+func (p *parser) number(t lexer.Token) (*Expr, error) {
+	if i, err := strconv.Atoi(t.Text); err == nil {
+		return Lit(i), nil
+	}
+	f, err := strconv.ParseFloat(t.Text, 64)
+	if err != nil {
+		return nil, &SyntaxError{Pos: t.Pos, Message: fmt.Sprintf("Invalid number %q", t.Text)}
+	}
+	return Lit(f), nil
+}
+
+func (p *parser) parsePrimary() (*Expr, error) {
+	t := p.next()
+	switch t.Kind {
+	case lexer.Number:
+		return p.number(t)
+	case lexer.String:
+		return Lit(t.Text), nil
+	case lexer.Ident:
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		if t.Quoted {
+			return Col(t.Text), nil
+		}
+		switch strings.ToLower(t.Text) {
+		case "true", "false":
+			return Lit(strings.EqualFold(t.Text, "true")), nil
+		case "null":
+			return Lit(nil), nil
+		}
+		return Col(t.Text), nil
+	case lexer.Symbol:
+		if t.Text == "(" {
+			e, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, p.errorAt(p.peek(), `Expected ")"`)
+			}
+			return e, nil
+		}
+	}
+	return nil, p.errorAt(t, "Expected a column, value or function")
+}
+
+// parseCall parses the arguments of a function call, after the opening parenthesis
+func (p *parser) parseCall(name lexer.Token) (*Expr, error) {
+	args := []*Expr{}
+	for {
+		if _, ok := p.accept(")"); ok {
+			break
+		}
+		if len(args) > 0 {
+			if _, ok := p.accept(","); !ok {
+				return nil, p.errorAt(p.peek(), `Expected "," or ")"`)
+			}
+		}
+		arg, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+
+	function := strings.ToLower(name.Text)
+	unary := map[string]func(*Expr) *Expr{
+		"abs": (*Expr).Abs, "upper": (*Expr).Upper, "lower": (*Expr).Lower, "trim": (*Expr).Trim, "len": (*Expr).Len,
+	}
+	switch {
+	case unary[function] != nil && len(args) == 1:
+		return unary[function](args[0]), nil
+	case function == "round" && len(args) == 1:
+		return args[0].Round(0), nil
+	case function == "round" && len(args) == 2:
+		decimals, ok := args[1].value.(int)
+		if args[1].op != "lit" || !ok {
+			return nil, &SyntaxError{Pos: name.Pos, Message: "The decimals of round must be an int"}
+		}
+		return args[0].Round(decimals), nil
+	case function == "coalesce" && len(args) > 0:
+		others := make([]any, len(args)-1)
+		for i, arg := range args[1:] {
+			others[i] = arg
+		}
+		return args[0].Coalesce(others...), nil
+	case unary[function] != nil || function == "round" || function == "coalesce":
+		return nil, &SyntaxError{Pos: name.Pos, Message: fmt.Sprintf("Wrong number of arguments for %s", function)}
+	}
+	return nil, &SyntaxError{Pos: name.Pos, Message: fmt.Sprintf("Unknown function %q", name.Text)}
+}
@@ -0,0 +1,137 @@
+package expr
+
+import (
+	"fmt"
+	"slices"
+	"teddy/dataframe/series"
+	"time"
+)
+
+// ResultType checks the operand types of the expression and returns the type of its result.
+//
+// The types of the columns are given by name, using the type names of a Schema:
+// "int", "float64", "string", "bool", "time" and "any". Columns of type "any" and null
+// literals are accepted by any operation. Returns an error wrapping series.ErrTypeConversion
+// if an operand has the wrong type, or an error if a column doesn't exist.
+func (e *Expr) ResultType(columns map[string]string) (string, error) {
+	switch e.op {
+	case "col":
+		typ, ok := columns[e.name]
+		if !ok {
+			return "", fmt.Errorf("Column not found: \"%s\"", e.name)
+		}
+		return typ, nil
+	case "lit":
+		return literalType(e.value), nil
+	}
+
+	args := make([]string, len(e.args))
+	for i, arg := range e.args {
+		typ, err := arg.ResultType(columns)
+		if err != nil {
+			return "", err
+		}
+		args[i] = typ
+	}
+
+	// expect checks that the operand has one of the types
+	expect := func(i int, types ...string) error {
+		if args[i] == "any" || slices.Contains(types, args[i]) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is %s, expected %v in %s", series.ErrTypeConversion, e.args[i], args[i], types, e)
+	}
+
+	switch e.op {
+	case "add", "sub", "mul", "div":
+		for i := range args {
+			if err := expect(i, "int", "float64"); err != nil {
+				return "", err
+			}
+		}
+		if e.op != "div" && args[0] == "int" && args[1] == "int" {
+			return "int", nil
+		}
+		return "float64", nil
+	case "abs", "round":
+		return args[0], expect(0, "int", "float64")
+	case "eq", "ne", "gt", "ge", "lt", "le":
+		if _, err := commonType(args); err != nil {
+			return "", fmt.Errorf("%w in %s", err, e)
+		}
+		return "bool", nil
+	case "and", "or", "not":
+		for i := range args {
+			if err := expect(i, "bool"); err != nil {
+				return "", err
+			}
+		}
+		return "bool", nil
+	case "is_null", "is_not_null":
+		return "bool", nil
+	case "contains", "starts_with", "ends_with":
+		return "bool", expect(0, "string")
+	case "upper", "lower", "trim":
+		return "string", expect(0, "string")
+	case "len":
+		return "int", expect(0, "string")
+	case "coalesce":
+		typ, err := commonType(args)
+		if err != nil {
+			return "", fmt.Errorf("%w in %s", err, e)
+		}
+		return typ, nil
+	case "when":
+		values := []string{}
+		for i := range args {
+			if i%2 == 0 && i+1 < len(args) {
+				if err := expect(i, "bool"); err != nil {
+					return "", err
+				}
+			} else {
+				values = append(values, args[i])
+			}
+		}
+		typ, err := commonType(values)
+		if err != nil {
+			return "", fmt.Errorf("%w in %s", err, e)
+		}
+		return typ, nil
+	}
+	return "", fmt.Errorf("Unknown operation \"%s\"", e.op)
+}
+
+// commonType returns the type that values of all the types can share.
+// Ints and floats share float64, and "any" is ignored.
+func commonType(types []string) (string, error) {
+	common := "any"
+	for _, typ := range types {
+		switch {
+		case typ == "any" || typ == common:
+		case common == "any":
+			common = typ
+		case (common == "int" && typ == "float64") || (common == "float64" && typ == "int"):
+			common = "float64"
+		default:
+			return "", fmt.Errorf("%w: %s and %s are not compatible", series.ErrTypeConversion, common, typ)
+		}
+	}
+	return common, nil
+}
+
+// literalType returns the type name of a literal value
+func literalType(value any) string {
+	switch value.(type) {
+	case int:
+		return "int"
+	case float64:
+		return "float64"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case time.Time:
+		return "time"
+	}
+	return "any"
+}
@@ -0,0 +1,104 @@
+package dataframe
+
+import (
+	"fmt"
+	"teddy/dataframe/expr"
+	"teddy/dataframe/series"
+)
+
+// evaluate computes an expression over the columns of the DataFrame
+func (df *DataFrame) evaluate(e *expr.Expr) (series.SeriesInterface, error) {
+	if missing := df.findColumnsThatDontExist(e.Columns()); len(missing) > 0 {
+		return nil, columnNotFound(missing...)
+	}
+	return e.Evaluate(df.series, df.Height())
+}
+
+// WithColumns adds a column for each expression, named by the expression.
+//
+// A column with the same name is replaced. All expressions are evaluated
+// against the original DataFrame, so they can't use each other's results.
+//
+//	df.WithColumns(expr.Col("a").Mul(expr.Col("b")).Alias("c"))
+func (df *DataFrame) WithColumns(exprs ...*expr.Expr) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	columns := make([]series.SeriesInterface, len(exprs))
+	for i, e := range exprs {
+		s, err := df.evaluate(e)
+		if err != nil {
+			return df.withError(err)
+		}
+		columns[i] = s
+	}
+
+	result := df.target()
+	for _, s := range columns {
+		result.setSeries(s)
+	}
+	return result
+}
+
+// Filter returns the rows where the expression is true. Rows where it is null are dropped.
+//
+//	df.Filter(expr.Col("age").Ge(18).And(expr.Col("name").StartsWith("J")))
+func (df *DataFrame) Filter(e *expr.Expr) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	s, err := df.evaluate(e)
+	if err != nil {
+		return df.withError(err)
+	}
+	if chunked, ok := s.(*series.ChunkedSeries); ok {
+		s = chunked.Compact()
+	}
+	mask, ok := s.(*series.BoolSeries)
+	if !ok {
+		return df.withError(fmt.Errorf("%w: filter expression %s is not bool", ErrTypeConversion, e))
+	}
+
+	rows := []int{}
+	for i, keep := range mask.All() {
+		if keep && !mask.IsNull(i) {
+			rows = append(rows, i)
+		}
+	}
+
+	result := df.takeRows(rows)
+	if df.inPlace {
+		df.series, df.index = result.series, result.index
+		return df
+	}
+	return result
+}
+
+// Eval adds a column computed from a formula, or replaces the column with the same name.
+//
+// The formula is an assignment such as "margin = (price - cost) / price", see expr.Parse
+// for the syntax. Operand types are checked against the Schema before anything is evaluated.
+// Sets an error if the formula is invalid, uses a missing column, or mixes incompatible types.
+func (df *DataFrame) Eval(assignment string) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	e, err := expr.ParseAssignment(assignment)
+	if err != nil {
+		return df.withError(err)
+	}
+	if missing := df.findColumnsThatDontExist(e.Columns()); len(missing) > 0 {
+		return df.withError(columnNotFound(missing...))
+	}
+	types := make(map[string]string)
+	for _, field := range df.Schema().Fields {
+		types[field.Name] = field.Type
+	}
+	if _, err := e.ResultType(types); err != nil {
+		return df.withError(err)
+	}
+	return df.WithColumns(e)
+}
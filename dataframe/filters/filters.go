@@ -52,7 +52,7 @@ func ApplyToDF(df *dataframe.DataFrame, colName string, filter Filter) *datafram
 			filteredValues[i] = s.Get(row)
 		}
 
-		result.AddSeries(series.NewSeries(col, filteredValues))
+		result = result.AddSeries(series.NewSeries(col, filteredValues))
 	}
 
 	return result
@@ -1,6 +1,8 @@
 package filters_test
 
 import (
+	"errors"
+	"slices"
 	"teddy/dataframe"
 	"teddy/dataframe/filters"
 	"teddy/dataframe/series"
@@ -195,3 +197,97 @@ func TestAdvancedFilters(t *testing.T) {
 		t.Errorf("Expected 3 indices, got %d", len(complexIndices))
 	}
 }
+
+func TestQuery(t *testing.T) {
+	// Tests that string queries select the expected rows
+	df := dataframe.NewDataFrame(
+		series.NewStringSeries("Name", []string{"Ann", "Bob", "Cid", "Dee"}),
+		series.NewIntSeries("Age", []int{25, 35, 45, 31}),
+		series.NewStringSeriesWithNulls("City", []string{"London", "Paris", "London", ""}, []bool{false, false, false, true}),
+		series.NewBoolSeries("Active", []bool{true, false, true, true}),
+	)
+
+	tests := []struct {
+		query string
+		names []any
+	}{
+		{"Age > 30 && City == 'London'", []any{"Cid"}},
+		{"Age > 30 AND City == 'Paris'", []any{"Bob"}},
+		{"30 < Age and not (City in ['Paris', \"Rome\"])", []any{"Cid", "Dee"}},
+		{"City not in ('London') || Age <= -1", []any{"Bob"}},
+		{"City == null", []any{"Dee"}},
+		{"City != null && !Active", []any{"Bob"}},
+		{"Active && Age >= 31.5", []any{"Cid"}},
+		{"Name < City", []any{"Ann", "Bob", "Cid"}},
+	}
+	for _, test := range tests {
+		result, err := filters.ApplyQuery(df, test.query)
+		if err != nil {
+			t.Errorf("Query %q returned error: %v", test.query, err)
+			continue
+		}
+		if names := result.GetSeries("Name").Values(); !slices.Equal(names, test.names) {
+			t.Errorf("Query %q: expected %v, got %v", test.query, test.names, names)
+		}
+	}
+}
+
+func TestQueryQuotedNames(t *testing.T) {
+	// Tests that names quoted with backticks are columns even when they look like literals or keywords
+	df := dataframe.NewDataFrame(
+		series.NewStringSeries("null", []string{"a", "b"}),
+		series.NewBoolSeries("true", []bool{false, true}),
+		series.NewIntSeries("and", []int{1, 2}),
+	)
+
+	tests := []struct {
+		query string
+		rows  []any
+	}{
+		{"`null` == 'b'", []any{"b"}},
+		{"`true`", []any{"b"}},
+		{"`and` > 1 and `null` != null", []any{"b"}},
+	}
+	for _, test := range tests {
+		result, err := filters.ApplyQuery(df, test.query)
+		if err != nil {
+			t.Errorf("Query %q returned error: %v", test.query, err)
+			continue
+		}
+		if rows := result.GetSeries("null").Values(); !slices.Equal(rows, test.rows) {
+			t.Errorf("Query %q: expected %v, got %v", test.query, test.rows, rows)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	// Tests that syntax errors report their position
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"Age > ", 6},
+		{"Age > 30 &&& City", 11},
+		{"(Age > 30", 9},
+		{"City == 'London", 8},
+		{"Age # 3", 4},
+		{"City in ['a' 'b']", 13},
+		{"30 > 20", 0},
+	}
+	for _, test := range tests {
+		_, err := filters.ParseQuery(test.query)
+		var queryErr *filters.QueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("Query %q: expected a QueryError, got %v", test.query, err)
+			continue
+		}
+		if queryErr.Pos != test.pos {
+			t.Errorf("Query %q: expected error at position %d, got %v", test.query, test.pos, err)
+		}
+	}
+
+	df := dataframe.NewDataFrame(series.NewIntSeries("Age", []int{1}))
+	if _, err := filters.ApplyQuery(df, "Height > 3"); !errors.Is(err, dataframe.ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", err)
+	}
+}
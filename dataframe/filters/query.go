@@ -0,0 +1,393 @@
+package filters
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"teddy/dataframe"
+	"teddy/dataframe/internal/lexer"
+	"teddy/dataframe/series"
+)
+
+// Query is a filter expression over the columns of a DataFrame, parsed from a string such as
+//
+//	Age > 30 && (City == 'London' || City in ['Paris', 'Rome']) && Email != null
+//
+// The language has column names, string, number, true, false and null literals,
+// the comparisons == != < <= > >=, && || ! (or and, or, not), in and not in lists,
+// and null checks with == null and != null. Comparisons are false for null values.
+// Names with spaces or symbols can be quoted with backticks.
+type Query struct {
+	text    string
+	root    queryNode
+	columns []string
+}
+
+// QueryError is a syntax error in a query, at a position counted in characters from 0
+type QueryError = lexer.Error
+
+// queryNode is a compiled part of a query, evaluated for a row
+type queryNode interface {
+	match(row func(column string) any) bool
+}
+
+// leafNode checks the value of a column with a filter
+type leafNode struct {
+	column string
+	filter Filter
+}
+
+func (n *leafNode) match(row func(string) any) bool { return n.filter(row(n.column)) }
+
+// columnsNode compares two columns, building the filter from the value of the second column
+type columnsNode struct {
+	left, right string
+	filter      func(other any) Filter
+}
+
+func (n *columnsNode) match(row func(string) any) bool {
+	other := row(n.right)
+	return other != nil && And(IsNotNull(), n.filter(other))(row(n.left))
+}
+
+// logicalNode combines nodes with && (and is true) or || (and is false)
+type logicalNode struct {
+	and         bool
+	left, right queryNode
+}
+
+func (n *logicalNode) match(row func(string) any) bool {
+	if n.and {
+		return n.left.match(row) && n.right.match(row)
+	}
+	return n.left.match(row) || n.right.match(row)
+}
+
+// notNode negates a node
+type notNode struct {
+	operand queryNode
+}
+
+func (n *notNode) match(row func(string) any) bool { return !n.operand.match(row) }
+
+// ParseQuery parses a query. Syntax errors are returned as a *QueryError with the position.
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := queryLexer.Tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().Kind != lexer.EOF {
+		return nil, p.unexpected("end of query")
+	}
+	return &Query{text: query, root: root, columns: p.columns}, nil
+}
+
+// String returns the text of the query
+func (q *Query) String() string {
+	return q.text
+}
+
+// Columns returns the names of the columns the query reads, in order of first use
+func (q *Query) Columns() []string {
+	return slices.Clone(q.columns)
+}
+
+// Apply returns the rows of the DataFrame that match the query
+func (q *Query) Apply(df *dataframe.DataFrame) (*dataframe.DataFrame, error) {
+	if df.Err() != nil {
+		return df, df.Err()
+	}
+	columns := make(map[string]series.SeriesInterface)
+	for _, column := range q.columns {
+		if !df.HasColumn(column) {
+			return nil, fmt.Errorf("%w: %q", dataframe.ErrColumnNotFound, column)
+		}
+		columns[column] = df.GetSeries(column)
+	}
+
+	drop := []int{}
+	for i := 0; i < df.Height(); i++ {
+		row := func(column string) any { return columns[column].Get(i) }
+		if !q.root.match(row) {
+			drop = append(drop, i)
+		}
+	}
+	result := df.DropRows(drop...)
+	return result, result.Err()
+}
+
+// ApplyQuery parses the query and returns the rows of the DataFrame that match it
+func ApplyQuery(df *dataframe.DataFrame, query string) (*dataframe.DataFrame, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return q.Apply(df)
+}
+
+// describe returns the token as shown in error messages
+func describe(t lexer.Token) string {
+	switch t.Kind {
+	case lexer.EOF:
+		return "end of query"
+	case lexer.String:
+		return strconv.Quote(t.Text)
+	}
+	return fmt.Sprintf("%q", t.Text)
+}
+
+// queryLexer splits queries into tokens. Names can contain dots.
+var queryLexer = &lexer.Lexer{
+	Symbols:    []string{"==", "!=", "<=", ">=", "&&", "||", "<>", "(", ")", "[", "]", ",", "<", ">", "=", "!", "-"},
+	IdentRunes: ".",
+}
+
+// queryParser is a recursive descent parser for queries
+type queryParser struct {
+	tokens  []lexer.Token
+	pos     int
+	columns []string
+}
+
+func (p *queryParser) peek() lexer.Token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() lexer.Token {
+	t := p.tokens[p.pos]
+	if t.Kind != lexer.EOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the symbols or words, ignoring case for words
+func (p *queryParser) accept(texts ...string) bool {
+	t := p.peek()
+	for _, text := range texts {
+		if (t.Kind == lexer.Symbol && t.Text == text) || isWord(t, text) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+// isWord reports whether the token is the given word, ignoring case.
+// A quoted name is never a word, so columns can be named like keywords and literals.
+func isWord(t lexer.Token, word string) bool {
+	return t.Kind == lexer.Ident && !t.Quoted && strings.EqualFold(t.Text, word)
+}
+
+func (p *queryParser) unexpected(expected string) error {
+	t := p.peek()
+	return &QueryError{Pos: t.Pos, Message: fmt.Sprintf("Expected %s, found %s", expected, describe(t))}
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("||", "or") {
+		var right queryNode
+		right, err = p.parseAnd()
+		left = &logicalNode{and: false, left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseNot()
+	for err == nil && p.accept("&&", "and") {
+		var right queryNode
+		right, err = p.parseNot()
+		left = &logicalNode{and: true, left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *queryParser) parseNot() (queryNode, error) {
+	if p.accept("!", "not") {
+		operand, err := p.parseNot()
+		return &notNode{operand: operand}, err
+	}
+	if p.accept("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.unexpected(`")"`)
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+// operand is a column or a literal value
+type operand struct {
+	column   string
+	value    any
+	isColumn bool
+}
+
+func (p *queryParser) parseOperand() (operand, error) {
+	t := p.peek()
+	switch {
+	case t.Kind == lexer.String:
+		p.next()
+		return operand{value: t.Text}, nil
+	case t.Kind == lexer.Number:
+		p.next()
+		return p.number(t)
+	case isWord(t, "true"), isWord(t, "false"):
+		p.next()
+		return operand{value: strings.EqualFold(t.Text, "true")}, nil
+	case isWord(t, "null"):
+		p.next()
+		return operand{}, nil
+	case t.Kind == lexer.Symbol && t.Text == "-" && p.tokens[p.pos+1].Kind == lexer.Number:
+		p.next()
+		number := p.next()
+		number.Text = "-" + number.Text
+		return p.number(number)
+	case t.Kind == lexer.Ident && !t.Quoted && isQueryKeyword(t.Text):
+		return operand{}, p.unexpected("a column or value")
+	case t.Kind == lexer.Ident:
+		p.next()
+		if !slices.Contains(p.columns, t.Text) {
+			p.columns = append(p.columns, t.Text)
+		}
+		return operand{column: t.Text, isColumn: true}, nil
+	}
+	return operand{}, p.unexpected("a column or value")
+}
+
+// number parses a number token as an int, or a float64 if it has a decimal point
+func (p *queryParser) number(t lexer.Token) (operand, error) {
+	if i, err := strconv.Atoi(t.Text); err == nil {
+		return operand{value: i}, nil
+	}
+	f, err := strconv.ParseFloat(t.Text, 64)
+	if err != nil {
+		return operand{}, &QueryError{Pos: t.Pos, Message: fmt.Sprintf("Invalid number %q", t.Text)}
+	}
+	return operand{value: f}, nil
+}
+
+// isQueryKeyword reports whether the word is an operator that can't be a column name
+func isQueryKeyword(word string) bool {
+	return slices.Contains([]string{"and", "or", "not", "in"}, strings.ToLower(word))
+}
+
+// comparisons maps each comparison to its filter and the comparison with the operands swapped
+var comparisons = map[string]struct {
+	filter  func(any) Filter
+	swapped string
+}{
+	"==": {Equal, "=="},
+	"=":  {Equal, "="},
+	"!=": {NotEqual, "!="},
+	"<>": {NotEqual, "<>"},
+	"<":  {LessThan, ">"},
+	"<=": {LessEqual, ">="},
+	">":  {GreaterThan, "<"},
+	">=": {GreaterEqual, "<="},
+}
+
+// parseComparison parses a comparison, an in list or a bool column
+func (p *queryParser) parseComparison() (queryNode, error) {
+	start := p.peek()
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	// in and not in lists
+	negated := isWord(p.peek(), "not") && isWord(p.tokens[p.pos+1], "in")
+	if negated {
+		p.next()
+	}
+	if p.accept("in") {
+		if !left.isColumn {
+			return nil, &QueryError{Pos: start.Pos, Message: "Expected a column before in"}
+		}
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		filter := In(values...)
+		if negated {
+			filter = Not(filter)
+		}
+		return &leafNode{column: left.column, filter: And(IsNotNull(), filter)}, nil
+	}
+
+	op := p.peek()
+	comparison, ok := comparisons[op.Text]
+	if op.Kind != lexer.Symbol || !ok {
+		// A column on its own must be a bool column that is true
+		if left.isColumn {
+			return &leafNode{column: left.column, filter: Equal(true)}, nil
+		}
+		return nil, p.unexpected("a comparison")
+	}
+	p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case left.isColumn && right.isColumn:
+		return &columnsNode{left: left.column, right: right.column, filter: comparison.filter}, nil
+	case !left.isColumn && !right.isColumn:
+		return nil, &QueryError{Pos: start.Pos, Message: "Expected a column in the comparison"}
+	case !left.isColumn:
+		// Put the column on the left, so 30 < Age is Age > 30
+		left, right = right, left
+		comparison = comparisons[comparison.swapped]
+	}
+
+	if right.value == nil {
+		switch op.Text {
+		case "==", "=":
+			return &leafNode{column: left.column, filter: IsNull()}, nil
+		case "!=", "<>":
+			return &leafNode{column: left.column, filter: IsNotNull()}, nil
+		}
+		return nil, &QueryError{Pos: op.Pos, Message: "Only == and != can compare with null"}
+	}
+	return &leafNode{column: left.column, filter: And(IsNotNull(), comparison.filter(right.value))}, nil
+}
+
+// parseList parses a list of values in brackets or parentheses
+func (p *queryParser) parseList() ([]any, error) {
+	closing := "]"
+	if p.accept("(") {
+		closing = ")"
+	} else if !p.accept("[") {
+		return nil, p.unexpected(`"[" or "("`)
+	}
+
+	values := []any{}
+	for !p.accept(closing) {
+		if len(values) > 0 && !p.accept(",") {
+			return nil, p.unexpected(fmt.Sprintf(`"," or %q`, closing))
+		}
+		t := p.peek()
+		value, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if value.isColumn {
+			return nil, &QueryError{Pos: t.Pos, Message: "Expected a value in the list, found column " + strconv.Quote(value.column)}
+		}
+		values = append(values, value.value)
+	}
+	return values, nil
+}
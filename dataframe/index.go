@@ -0,0 +1,277 @@
+package dataframe
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"teddy/dataframe/series"
+)
+
+var (
+	// ErrNoIndex is returned when a label lookup is used on a DataFrame without an index
+	ErrNoIndex = errors.New("DataFrame has no index")
+
+	// ErrLabelNotFound is returned when no row has the label
+	ErrLabelNotFound = errors.New("label not found")
+)
+
+// rowIndex labels the rows of a DataFrame with the values of one or more columns.
+//
+// The lookup structures are built on first use: the rows sorted by label for ranges
+// and repeated labels, and a hash of the labels when no two of them compare equal.
+// Labels are always matched with compareLabel, the hash only finds the row faster.
+// An index is never modified, operations that change the rows create a new one.
+type rowIndex struct {
+	series []series.SeriesInterface
+
+	once   sync.Once
+	hash   map[string]int
+	sorted []int
+}
+
+func newRowIndex(s []series.SeriesInterface) *rowIndex {
+	return &rowIndex{series: s}
+}
+
+// build creates the sorted rows, and the hash of the labels if they are unique.
+// Rows with null labels are never found, so they are not in the hash.
+func (index *rowIndex) build() {
+	index.once.Do(func() {
+		height := index.series[0].Len()
+		index.sorted = allRows(height)
+		slices.SortStableFunc(index.sorted, func(a, b int) int {
+			return index.compareLabel(a, index.label(b))
+		})
+
+		// Equal labels are next to each other once sorted, and nulls are last
+		index.hash = make(map[string]int, height)
+		for i, row := range index.sorted {
+			label := index.label(row)
+			if slices.Contains(label, nil) {
+				break
+			}
+			if i > 0 && index.compareLabel(index.sorted[i-1], label) == 0 {
+				index.hash = nil
+				break
+			}
+			index.hash[rowKey(index.series, row)] = row
+		}
+	})
+}
+
+// label returns the values of the index columns for a row
+func (index *rowIndex) label(row int) []any {
+	values := make([]any, len(index.series))
+	for i, s := range index.series {
+		values[i] = s.Get(row)
+	}
+	return values
+}
+
+// compareLabel compares the label of a row with a label, column by column. Nulls sort last.
+func (index *rowIndex) compareLabel(row int, label []any) int {
+	for i, s := range index.series {
+		a, b := s.Get(row), label[i]
+		switch {
+		case a == nil && b == nil:
+			continue
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		}
+		if result := compareValues(a, b); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// find returns the rows with the label, in row order.
+//
+// The hash is tried first. A label it doesn't find may still compare equal to one of
+// another type, such as "1" and 1, so the sorted rows are searched too.
+func (index *rowIndex) find(label []any) []int {
+	index.build()
+	if index.hash != nil {
+		if row, ok := index.hash[valuesKey(label)]; ok && index.compareLabel(row, label) == 0 {
+			return []int{row}
+		}
+	}
+
+	rows := index.between(label, label)
+	slices.Sort(rows)
+	return rows
+}
+
+// between returns the rows with labels from start to end, inclusive, in label order.
+// A nil bound is unbounded. Rows with null labels are never included.
+func (index *rowIndex) between(start, end []any) []int {
+	index.build()
+	from := 0
+	if start != nil {
+		from, _ = slices.BinarySearchFunc(index.sorted, start, index.compareLabel)
+	}
+	to := len(index.sorted)
+	if end != nil {
+		// The first row after end
+		to, _ = slices.BinarySearchFunc(index.sorted, end, func(row int, label []any) int {
+			if index.compareLabel(row, label) <= 0 {
+				return -1
+			}
+			return 1
+		})
+	}
+
+	rows := []int{}
+	for _, row := range index.sorted[from:max(from, to)] {
+		if !slices.Contains(index.label(row), nil) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// takeRows returns the index of the given rows
+func (index *rowIndex) takeRows(rows []int) *rowIndex {
+	if index == nil {
+		return nil
+	}
+	taken := make([]series.SeriesInterface, len(index.series))
+	for i, s := range index.series {
+		taken[i] = takeRows(s, rows)
+	}
+	return newRowIndex(taken)
+}
+
+// dropRows returns the index without the given rows
+func (index *rowIndex) dropRows(rows []int) *rowIndex {
+	if index == nil {
+		return nil
+	}
+	kept := make([]series.SeriesInterface, len(index.series))
+	for i, s := range index.series {
+		kept[i] = s.DropRows(rows...)
+	}
+	return newRowIndex(kept)
+}
+
+// SetIndex labels the rows with the values of the columns, which are removed from the columns.
+//
+// The labels are used by Loc and LocRange, and are kept by operations that select,
+// filter, sort or drop rows. A previous index is discarded.
+// Sets an error if a column doesn't exist.
+func (df *DataFrame) SetIndex(columns ...string) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+	if missing := df.findColumnsThatDontExist(columns); len(missing) > 0 {
+		return df.withError(columnNotFound(missing...))
+	}
+	if len(columns) == 0 {
+		return df.ResetIndex()
+	}
+
+	indexSeries := make([]series.SeriesInterface, len(columns))
+	for i, column := range columns {
+		indexSeries[i] = df.GetSeries(column)
+	}
+	result := df.target()
+	result.series = slices.DeleteFunc(result.series, func(s series.SeriesInterface) bool {
+		return slices.Contains(columns, s.Name())
+	})
+	result.index = newRowIndex(indexSeries)
+	return result
+}
+
+// ResetIndex moves the index columns back to the start of the columns and removes the index
+func (df *DataFrame) ResetIndex() *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	result := df.target()
+	if result.index != nil {
+		result.series = append(slices.Clone(result.index.series), result.series...)
+		result.index = nil
+	}
+	return result
+}
+
+// IndexNames returns the names of the index columns, or nil if the DataFrame has no index
+func (df *DataFrame) IndexNames() []string {
+	if df.index == nil {
+		return nil
+	}
+	names := make([]string, len(df.index.series))
+	for i, s := range df.index.series {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// Loc returns the rows with the label. For an index of several columns, the label is a []any
+// with a value for each column.
+//
+// A row has the label if its values compare equal to it as when sorting, so "1" finds
+// the int label 1 whether labels repeat or not. Unique labels are found with a hash
+// lookup, repeated labels with a binary search.
+// Sets ErrNoIndex if the DataFrame has no index, or ErrLabelNotFound if no row has the label.
+func (df *DataFrame) Loc(label any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+	values, err := df.labelValues(label)
+	if err != nil {
+		return df.withError(err)
+	}
+
+	rows := df.index.find(values)
+	if len(rows) == 0 {
+		return df.withError(fmt.Errorf("%w: %v", ErrLabelNotFound, label))
+	}
+	return df.takeRows(rows)
+}
+
+// LocRange returns the rows with labels from start to end, inclusive, sorted by label.
+// A nil bound is unbounded. Rows with null labels are not included.
+//
+// Sets ErrNoIndex if the DataFrame has no index.
+func (df *DataFrame) LocRange(start, end any) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	var startValues, endValues []any
+	var err error
+	if start != nil {
+		if startValues, err = df.labelValues(start); err != nil {
+			return df.withError(err)
+		}
+	}
+	if end != nil {
+		if endValues, err = df.labelValues(end); err != nil {
+			return df.withError(err)
+		}
+	}
+	if df.index == nil {
+		return df.withError(ErrNoIndex)
+	}
+	return df.takeRows(df.index.between(startValues, endValues))
+}
+
+// labelValues returns the value of a label for each index column
+func (df *DataFrame) labelValues(label any) ([]any, error) {
+	if df.index == nil {
+		return nil, ErrNoIndex
+	}
+	values := []any{label}
+	if len(df.index.series) > 1 {
+		values, _ = label.([]any)
+	}
+	if len(values) != len(df.index.series) {
+		return nil, fmt.Errorf("%w: label %v has %d values, the index has %d columns", ErrLengthMismatch, label, len(values), len(df.index.series))
+	}
+	return values, nil
+}
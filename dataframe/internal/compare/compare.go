@@ -0,0 +1,50 @@
+// Package compare orders single values for the dataframe packages
+package compare
+
+import (
+	"cmp"
+	"strings"
+	"time"
+)
+
+// Values compares two non-null values of the same kind: ints and float64s, which can be
+// mixed, strings, bools with false before true, or times.
+// Returns -1, 0 or +1, and false if the values are of kinds that can't be compared.
+func Values(a, b any) (int, bool) {
+	switch x := a.(type) {
+	case int:
+		switch y := b.(type) {
+		case int:
+			return cmp.Compare(x, y), true
+		case float64:
+			return cmp.Compare(float64(x), y), true
+		}
+	case float64:
+		switch y := b.(type) {
+		case int:
+			return cmp.Compare(x, float64(y)), true
+		case float64:
+			return cmp.Compare(x, y), true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return cmp.Compare(boolToInt(x), boolToInt(y)), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+	}
+	return 0, false
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+// Package lexer splits formulas and queries into tokens for the dataframe packages
+package lexer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Error is a syntax error at a position counted in characters from 0
+type Error struct {
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Pos)
+}
+
+// Kind is the kind of a token
+type Kind int
+
+const (
+	EOF Kind = iota
+	Ident
+	Number
+	String
+	Symbol
+)
+
+// Token is a word, literal or symbol, with its position in the text
+type Token struct {
+	Kind Kind
+	Text string
+	Pos  int
+	// Quoted is true for a quoted name, which is never a keyword or literal
+	Quoted bool
+}
+
+// Lexer describes the words and symbols of a language
+type Lexer struct {
+	// Symbols are the accepted symbols. Longer symbols are matched first.
+	Symbols []string
+
+	// IdentRunes are the runes accepted in names besides letters, digits and _
+	IdentRunes string
+
+	// NameQuotes are the runes that quote names, "`" if empty
+	NameQuotes string
+
+	// StringQuotes are the runes that quote strings, ' and " if empty
+	StringQuotes string
+
+	// DoubledQuotes escapes a quote by doubling it, as in SQL, instead of with a backslash
+	DoubledQuotes bool
+}
+
+// Tokenize splits the text into tokens, ending with an EOF token.
+//
+// Names start with a letter or _, or are quoted with backticks. Strings are quoted
+// with ' or ". A backslash escapes the next character in quotes. The quotes and
+// escapes can be changed with the fields of the Lexer.
+func (l *Lexer) Tokenize(text string) ([]Token, error) {
+	nameQuotes, stringQuotes := l.NameQuotes, l.StringQuotes
+	if nameQuotes == "" {
+		nameQuotes = "`"
+	}
+	if stringQuotes == "" {
+		stringQuotes = `'"`
+	}
+
+	symbols := slices.Clone(l.Symbols)
+	slices.SortStableFunc(symbols, func(a, b string) int { return len([]rune(b)) - len([]rune(a)) })
+	longest := 0
+	if len(symbols) > 0 {
+		longest = len([]rune(symbols[0]))
+	}
+
+	tokens := []Token{}
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || strings.ContainsRune(l.IdentRunes, runes[i])) {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: Ident, Text: string(runes[start:i]), Pos: start})
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: Number, Text: string(runes[start:i]), Pos: start})
+		case strings.ContainsRune(nameQuotes, r) || strings.ContainsRune(stringQuotes, r):
+			var builder strings.Builder
+			for i++; i < len(runes); i++ {
+				if runes[i] == r {
+					if !l.DoubledQuotes || i+1 >= len(runes) || runes[i+1] != r {
+						break
+					}
+					i++
+				} else if runes[i] == '\\' && !l.DoubledQuotes && i+1 < len(runes) {
+					i++
+				}
+				builder.WriteRune(runes[i])
+			}
+			isName := strings.ContainsRune(nameQuotes, r)
+			if i >= len(runes) {
+				if isName {
+					return nil, &Error{Pos: start, Message: "Unterminated name"}
+				}
+				return nil, &Error{Pos: start, Message: "Unterminated string"}
+			}
+			i++
+			kind := String
+			if isName {
+				kind = Ident
+			}
+			tokens = append(tokens, Token{Kind: kind, Text: builder.String(), Pos: start, Quoted: isName})
+		default:
+			rest := string(runes[i:min(i+longest, len(runes))])
+			index := slices.IndexFunc(symbols, func(symbol string) bool { return strings.HasPrefix(rest, symbol) })
+			if index == -1 {
+				return nil, &Error{Pos: start, Message: fmt.Sprintf("Unexpected character %q", r)}
+			}
+			i += len([]rune(symbols[index]))
+			tokens = append(tokens, Token{Kind: Symbol, Text: symbols[index], Pos: start})
+		}
+	}
+	return append(tokens, Token{Kind: EOF, Pos: len(runes)}), nil
+}
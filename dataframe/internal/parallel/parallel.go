@@ -0,0 +1,35 @@
+// Package parallel splits row ranges across goroutines for the dataframe packages
+package parallel
+
+import "sync"
+
+// Ranges splits the rows 0 to n-1 into at most workers consecutive ranges of about
+// equal size, as [start, end) pairs in order. It returns one range with one worker or less.
+func Ranges(n, workers int) [][2]int {
+	workers = max(1, min(workers, n))
+	size := (n + workers - 1) / max(1, workers)
+	ranges := [][2]int{}
+	for start := 0; start < n || len(ranges) == 0; start += size {
+		ranges = append(ranges, [2]int{start, min(start+size, n)})
+	}
+	return ranges
+}
+
+// Run calls f with each range and its position in ranges, and waits for all calls to return.
+// Each range runs on its own goroutine, except a single range which runs on the calling goroutine.
+func Run(ranges [][2]int, f func(i, start, end int)) {
+	if len(ranges) == 1 {
+		f(0, ranges[0][0], ranges[0][1])
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i, r := range ranges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f(i, r[0], r[1])
+		}()
+	}
+	wg.Wait()
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"teddy/dataframe/series"
@@ -76,7 +77,13 @@ func ReadCSVtoColumns(path string, options ...OptionsMap) ([][]string, error) {
 }
 
 // ReadParquet reads a Parquet file and returns a DataFrame
+//
+// Options:
+//   - columns: []string (default: nil) Only read these columns, in this order. Reads all columns if nil.
 func ReadParquet(filename string, options ...OptionsMap) (*DataFrame, error) {
+	optionsClean := standardizeOptions(options...)
+	columns, _ := optionsClean.getOption("columns", nil).([]string)
+
 	fr, err := local.NewLocalFileReader(filename)
 	if err != nil {
 		return nil, fmt.Errorf("Error reading parquet file: %s: %w", filename, err)
@@ -96,6 +103,9 @@ func ReadParquet(filename string, options ...OptionsMap) (*DataFrame, error) {
 
 	for i := int64(0); i < colCount; i++ {
 		colName := pr.SchemaHandler.GetExName(int(i) + 1)
+		if columns != nil && !slices.Contains(columns, colName) {
+			continue
+		}
 		values, _, _, err := pr.ReadColumnByIndex(i, rowCount)
 		if err != nil {
 			return nil, fmt.Errorf("Error reading column %d: %w", i, err)
@@ -181,16 +191,46 @@ func ReadParquet(filename string, options ...OptionsMap) (*DataFrame, error) {
 				seriess = series.NewGenericSeries(colName, values)
 			}
 
-			df.AddSeries(seriess)
+			df = df.AddSeries(seriess)
 		} else {
 			// Empty column - create an empty generic series
-			df.AddSeries(series.NewGenericSeries(colName, []any{}))
+			df = df.AddSeries(series.NewGenericSeries(colName, []any{}))
 		}
 	}
 
+	if columns != nil {
+		selected := make([]any, len(columns))
+		for i, column := range columns {
+			selected[i] = column
+		}
+		df = df.Select(selected...)
+		return df, df.Err()
+	}
+
 	return df, nil
 }
 
+// readParquetColumnNames returns the names of the columns of a Parquet file without reading the data
+func readParquetColumnNames(filename string) ([]string, error) {
+	fr, err := local.NewLocalFileReader(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading parquet file: %s: %w", filename, err)
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetColumnReader(fr, 1)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating parquet column reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	names := make([]string, pr.SchemaHandler.GetColumnNum())
+	for i := range names {
+		names[i] = pr.SchemaHandler.GetExName(i + 1)
+	}
+	return names, nil
+}
+
 // NewFromRows creates a DataFrame from a 2D array of strings (rows)
 func NewFromRows(rows [][]string, options ...OptionsMap) *DataFrame {
 	optionsClean := standardizeOptions(options...)
@@ -224,30 +264,30 @@ func NewFromRows(rows [][]string, options ...OptionsMap) *DataFrame {
 			case "int":
 				intValues, ok := series.StringSliceToIntSlice(column)
 				if ok {
-					df.AddSeries(series.NewIntSeries(header[i], intValues))
+					df = df.AddSeries(series.NewIntSeries(header[i], intValues))
 				} else {
-					df.AddSeries(series.NewStringSeries(header[i], column))
+					df = df.AddSeries(series.NewStringSeries(header[i], column))
 				}
 			case "float":
 				floatValues, ok := series.StringSliceToFloat64Slice(column)
 				if ok {
-					df.AddSeries(series.NewFloat64Series(header[i], floatValues))
+					df = df.AddSeries(series.NewFloat64Series(header[i], floatValues))
 				} else {
-					df.AddSeries(series.NewStringSeries(header[i], column))
+					df = df.AddSeries(series.NewStringSeries(header[i], column))
 				}
 			case "bool":
 				boolValues, ok := series.StringSliceToBoolSlice(column)
 				if ok {
-					df.AddSeries(series.NewBoolSeries(header[i], boolValues))
+					df = df.AddSeries(series.NewBoolSeries(header[i], boolValues))
 				} else {
-					df.AddSeries(series.NewStringSeries(header[i], column))
+					df = df.AddSeries(series.NewStringSeries(header[i], column))
 				}
 			default:
-				df.AddSeries(series.NewStringSeries(header[i], column))
+				df = df.AddSeries(series.NewStringSeries(header[i], column))
 			}
 		} else {
 			// Default to string series
-			df.AddSeries(series.NewStringSeries(header[i], column))
+			df = df.AddSeries(series.NewStringSeries(header[i], column))
 		}
 	}
 
@@ -297,30 +337,30 @@ func NewFromColumns(columns [][]string, options ...OptionsMap) *DataFrame {
 			case "int":
 				intValues, ok := series.StringSliceToIntSlice(column)
 				if ok {
-					df.AddSeries(series.NewIntSeries(header[i], intValues))
+					df = df.AddSeries(series.NewIntSeries(header[i], intValues))
 				} else {
-					df.AddSeries(series.NewStringSeries(header[i], column))
+					df = df.AddSeries(series.NewStringSeries(header[i], column))
 				}
 			case "float":
 				floatValues, ok := series.StringSliceToFloat64Slice(column)
 				if ok {
-					df.AddSeries(series.NewFloat64Series(header[i], floatValues))
+					df = df.AddSeries(series.NewFloat64Series(header[i], floatValues))
 				} else {
-					df.AddSeries(series.NewStringSeries(header[i], column))
+					df = df.AddSeries(series.NewStringSeries(header[i], column))
 				}
 			case "bool":
 				boolValues, ok := series.StringSliceToBoolSlice(column)
 				if ok {
-					df.AddSeries(series.NewBoolSeries(header[i], boolValues))
+					df = df.AddSeries(series.NewBoolSeries(header[i], boolValues))
 				} else {
-					df.AddSeries(series.NewStringSeries(header[i], column))
+					df = df.AddSeries(series.NewStringSeries(header[i], column))
 				}
 			default:
-				df.AddSeries(series.NewStringSeries(header[i], column))
+				df = df.AddSeries(series.NewStringSeries(header[i], column))
 			}
 		} else {
 			// Default to string series
-			df.AddSeries(series.NewStringSeries(header[i], column))
+			df = df.AddSeries(series.NewStringSeries(header[i], column))
 		}
 	}
 
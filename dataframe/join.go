@@ -0,0 +1,101 @@
+package dataframe
+
+import (
+	"fmt"
+	"slices"
+	"teddy/dataframe/series"
+)
+
+// Join combines the columns of the DataFrames for rows with equal values in the on columns.
+//
+// The result has the columns of df followed by the columns of other, except the on columns.
+// Rows keep the order of df, with the matching rows of other in their order.
+// Null keys don't match any row.
+//
+// Options:
+//   - how: string (default: "inner") "inner" keeps rows with a match,
+//     "left" keeps all rows of df with nulls where other has no match.
+//   - suffix: string (default: "_right") Added to the names of columns of other that are also in df.
+func (df *DataFrame) Join(other *DataFrame, on []string, options ...OptionsMap) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+	if other.err != nil {
+		return df.withError(other.err)
+	}
+
+	optionsClean := standardizeOptions(options...)
+	how := optionsClean.getOption("how", "inner").(string)
+	suffix := optionsClean.getOption("suffix", "_right").(string)
+	if how != "inner" && how != "left" {
+		return df.withError(fmt.Errorf("Unsupported join type: %s", how))
+	}
+
+	if missing := df.findColumnsThatDontExist(on); len(missing) > 0 {
+		return df.withError(columnNotFound(missing...))
+	}
+	if missing := other.findColumnsThatDontExist(on); len(missing) > 0 {
+		return df.withError(columnNotFound(missing...))
+	}
+
+	leftKeys := make([]series.SeriesInterface, len(on))
+	rightKeys := make([]series.SeriesInterface, len(on))
+	for i, col := range on {
+		leftKeys[i] = df.GetSeries(col)
+		rightKeys[i] = other.GetSeries(col)
+	}
+
+	// Index the rows of other by key
+	rightRows := make(map[string][]int)
+	for i := 0; i < other.Height(); i++ {
+		if !hasNullKey(rightKeys, i) {
+			key := rowKey(rightKeys, i)
+			rightRows[key] = append(rightRows[key], i)
+		}
+	}
+
+	// Match each row of df, using -1 for a missing row of other
+	leftIndexes := []int{}
+	rightIndexes := []int{}
+	for i := 0; i < df.Height(); i++ {
+		var matches []int
+		if !hasNullKey(leftKeys, i) {
+			matches = rightRows[rowKey(leftKeys, i)]
+		}
+		if len(matches) == 0 && how == "left" {
+			matches = []int{-1}
+		}
+		for _, match := range matches {
+			leftIndexes = append(leftIndexes, i)
+			rightIndexes = append(rightIndexes, match)
+		}
+	}
+
+	result := df.takeRows(leftIndexes)
+	for _, s := range other.series {
+		if slices.Contains(on, s.Name()) {
+			continue
+		}
+		name := s.Name()
+		if result.HasColumn(name) {
+			name += suffix
+		}
+		result.series = append(result.series, takeRows(s, rightIndexes).Rename(name))
+	}
+
+	if df.inPlace {
+		df.series, df.index = result.series, result.index
+		return df
+	}
+	return result
+}
+
+// hasNullKey returns true if any of the key series is null at the row
+func hasNullKey(keySeries []series.SeriesInterface, row int) bool {
+	for _, s := range keySeries {
+		if s.IsNull(row) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,422 @@
+package lazy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"teddy/dataframe"
+	"teddy/dataframe/aggregate"
+	"teddy/dataframe/expr"
+)
+
+// LazyFrame records operations on a data source and runs them when collected.
+//
+// The recorded plan is optimized before it runs: only the columns that are used
+// are read, filters are moved as close to the data source as possible, including
+// before joins, and adjacent selects are merged.
+// A file is still parsed in full: filters moved into a scan run on the loaded
+// DataFrame, before any other step.
+type LazyFrame struct {
+	plan node
+}
+
+// node is a step of a plan
+type node interface {
+	// outputColumns returns the names of the columns the step produces
+	outputColumns() ([]string, error)
+	// execute runs the step and its inputs
+	execute() (*dataframe.DataFrame, error)
+	// describe returns a one line description of the step
+	describe() string
+	// inputs returns the steps the step reads from
+	inputs() []node
+	// withInputs returns a copy of the step that reads from the given steps
+	withInputs(inputs []node) node
+}
+
+// Scan returns a LazyFrame that reads a CSV or Parquet file when collected.
+//
+// The options are passed to the DataFrameReader. The header and inferdatatypes
+// options default to true.
+func Scan(path string, options ...dataframe.OptionsMap) *LazyFrame {
+	scanOptions := dataframe.OptionsMap{"header": true, "inferdatatypes": true}
+	if len(options) > 0 {
+		for key, value := range options[0] {
+			scanOptions[strings.ToLower(key)] = value
+		}
+	}
+	return &LazyFrame{plan: &scanNode{path: path, options: scanOptions}}
+}
+
+// From returns a LazyFrame that reads from a DataFrame
+func From(df *dataframe.DataFrame) *LazyFrame {
+	return &LazyFrame{plan: &frameNode{df: df}}
+}
+
+// Select records keeping only the given columns, in order
+func (lf *LazyFrame) Select(columns ...string) *LazyFrame {
+	return &LazyFrame{plan: &selectNode{input: lf.plan, columns: columns}}
+}
+
+// Filter records keeping the rows where the expression is true
+func (lf *LazyFrame) Filter(predicate *expr.Expr) *LazyFrame {
+	return &LazyFrame{plan: &filterNode{input: lf.plan, predicate: predicate}}
+}
+
+// WithColumns records adding a column for each expression
+func (lf *LazyFrame) WithColumns(exprs ...*expr.Expr) *LazyFrame {
+	return &LazyFrame{plan: &withColumnsNode{input: lf.plan, exprs: exprs}}
+}
+
+// GroupBy records grouping by the given columns and aggregating the others, as aggregate.GroupBy
+func (lf *LazyFrame) GroupBy(by []string, aggregations map[string]aggregate.Aggregator) *LazyFrame {
+	return &LazyFrame{plan: &groupByNode{input: lf.plan, by: by, aggregations: aggregations}}
+}
+
+// Join records joining with other on the given columns, as DataFrame.Join
+//
+// Options:
+//   - how: string (default: "inner") "inner" or "left".
+//   - suffix: string (default: "_right") Added to the names of columns of other that are also in the LazyFrame.
+func (lf *LazyFrame) Join(other *LazyFrame, on []string, options ...dataframe.OptionsMap) *LazyFrame {
+	var option dataframe.OptionsMap
+	if len(options) > 0 {
+		option = options[0]
+	}
+	return &LazyFrame{plan: &joinNode{
+		left:   lf.plan,
+		right:  other.plan,
+		on:     on,
+		how:    option.Get("how", "inner").(string),
+		suffix: option.Get("suffix", "_right").(string),
+	}}
+}
+
+// Explain returns the optimized plan, one step per line with the inputs of each step indented below it
+func (lf *LazyFrame) Explain() (string, error) {
+	plan, err := optimize(lf.plan)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	var write func(n node, depth int)
+	write = func(n node, depth int) {
+		builder.WriteString(strings.Repeat("  ", depth) + n.describe() + "\n")
+		for _, input := range n.inputs() {
+			write(input, depth+1)
+		}
+	}
+	write(plan, 0)
+	return builder.String(), nil
+}
+
+// Collect optimizes and runs the plan and returns the resulting DataFrame
+func (lf *LazyFrame) Collect() (*dataframe.DataFrame, error) {
+	plan, err := optimize(lf.plan)
+	if err != nil {
+		return nil, err
+	}
+	return plan.execute()
+}
+
+// scanNode reads a file, keeping only the given columns, then the rows matching the filters.
+// The reader parses every row, so the filters only save the work of the later steps.
+type scanNode struct {
+	path    string
+	options dataframe.OptionsMap
+	// nil reads all columns
+	columns []string
+	filters []*expr.Expr
+}
+
+func (n *scanNode) reader() *dataframe.DataFrameReader {
+	reader := dataframe.Read().FilePath(n.path)
+	for key, value := range n.options {
+		reader = reader.Option(key, value)
+	}
+	return reader
+}
+
+func (n *scanNode) outputColumns() ([]string, error) {
+	if n.columns != nil {
+		return n.columns, nil
+	}
+	return n.reader().ColumnNames()
+}
+
+func (n *scanNode) execute() (*dataframe.DataFrame, error) {
+	reader := n.reader()
+	if n.columns != nil {
+		reader = reader.Select(n.columns...)
+	}
+	df, err := reader.Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range n.filters {
+		df = df.Filter(filter)
+	}
+	return df, df.Err()
+}
+
+func (n *scanNode) describe() string {
+	description := "Scan " + n.path
+	if n.columns != nil {
+		description += fmt.Sprintf(" columns=%v", n.columns)
+	}
+	if len(n.filters) > 0 {
+		description += fmt.Sprintf(" filters=%v", n.filters)
+	}
+	return description
+}
+
+func (n *scanNode) inputs() []node { return nil }
+
+func (n *scanNode) withInputs(inputs []node) node {
+	scan := *n
+	return &scan
+}
+
+// frameNode reads from a DataFrame
+type frameNode struct {
+	df *dataframe.DataFrame
+}
+
+func (n *frameNode) outputColumns() ([]string, error)       { return n.df.ColumnNames(), n.df.Err() }
+func (n *frameNode) execute() (*dataframe.DataFrame, error) { return n.df, n.df.Err() }
+func (n *frameNode) describe() string                       { return fmt.Sprintf("DataFrame %v", n.df.ColumnNames()) }
+func (n *frameNode) inputs() []node                         { return nil }
+func (n *frameNode) withInputs(inputs []node) node          { return &frameNode{df: n.df} }
+
+// selectNode keeps the given columns
+type selectNode struct {
+	input   node
+	columns []string
+}
+
+func (n *selectNode) outputColumns() ([]string, error) { return n.columns, nil }
+
+func (n *selectNode) execute() (*dataframe.DataFrame, error) {
+	df, err := n.input.execute()
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]any, len(n.columns))
+	for i, column := range n.columns {
+		selected[i] = column
+	}
+	df = df.Select(selected...)
+	return df, df.Err()
+}
+
+func (n *selectNode) describe() string { return fmt.Sprintf("Select %v", n.columns) }
+func (n *selectNode) inputs() []node   { return []node{n.input} }
+
+func (n *selectNode) withInputs(inputs []node) node {
+	return &selectNode{input: inputs[0], columns: n.columns}
+}
+
+// filterNode keeps the rows where the predicate is true
+type filterNode struct {
+	input     node
+	predicate *expr.Expr
+}
+
+func (n *filterNode) outputColumns() ([]string, error) { return n.input.outputColumns() }
+
+func (n *filterNode) execute() (*dataframe.DataFrame, error) {
+	df, err := n.input.execute()
+	if err != nil {
+		return nil, err
+	}
+	df = df.Filter(n.predicate)
+	return df, df.Err()
+}
+
+func (n *filterNode) describe() string { return fmt.Sprintf("Filter %s", n.predicate) }
+func (n *filterNode) inputs() []node   { return []node{n.input} }
+
+func (n *filterNode) withInputs(inputs []node) node {
+	return &filterNode{input: inputs[0], predicate: n.predicate}
+}
+
+// withColumnsNode adds a column for each expression
+type withColumnsNode struct {
+	input node
+	exprs []*expr.Expr
+}
+
+func (n *withColumnsNode) outputColumns() ([]string, error) {
+	columns, err := n.input.outputColumns()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range n.exprs {
+		if !slices.Contains(columns, e.Name()) {
+			columns = append(slices.Clone(columns), e.Name())
+		}
+	}
+	return columns, nil
+}
+
+func (n *withColumnsNode) execute() (*dataframe.DataFrame, error) {
+	df, err := n.input.execute()
+	if err != nil {
+		return nil, err
+	}
+	df = df.WithColumns(n.exprs...)
+	return df, df.Err()
+}
+
+func (n *withColumnsNode) describe() string { return fmt.Sprintf("WithColumns %v", n.exprs) }
+func (n *withColumnsNode) inputs() []node   { return []node{n.input} }
+
+func (n *withColumnsNode) withInputs(inputs []node) node {
+	return &withColumnsNode{input: inputs[0], exprs: n.exprs}
+}
+
+// groupByNode groups by columns and aggregates the others
+type groupByNode struct {
+	input        node
+	by           []string
+	aggregations map[string]aggregate.Aggregator
+}
+
+// aggregated returns the names of the aggregated columns, sorted
+func (n *groupByNode) aggregated() []string {
+	columns := []string{}
+	for column := range n.aggregations {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+	return columns
+}
+
+// missingColumn returns an error for the first group or aggregated column that isn't in the input columns.
+// GroupBy would skip an unknown aggregated column instead.
+func (n *groupByNode) missingColumn(columns []string) error {
+	for _, column := range append(slices.Clone(n.by), n.aggregated()...) {
+		// count doesn't read a column
+		if column != "count" && !slices.Contains(columns, column) {
+			return fmt.Errorf("%w: \"%s\"", dataframe.ErrColumnNotFound, column)
+		}
+	}
+	return nil
+}
+
+func (n *groupByNode) outputColumns() ([]string, error) {
+	columns, err := n.input.outputColumns()
+	if err != nil {
+		return nil, err
+	}
+	if err := n.missingColumn(columns); err != nil {
+		return nil, err
+	}
+	return n.output(), nil
+}
+
+// output returns the group columns followed by the aggregated columns, sorted.
+// Aggregations of the group columns are skipped by GroupBy.
+func (n *groupByNode) output() []string {
+	output := slices.Clone(n.by)
+	for _, column := range n.aggregated() {
+		if !slices.Contains(n.by, column) {
+			output = append(output, column)
+		}
+	}
+	return output
+}
+
+func (n *groupByNode) execute() (*dataframe.DataFrame, error) {
+	df, err := n.input.execute()
+	if err != nil {
+		return nil, err
+	}
+	if err := n.missingColumn(df.ColumnNames()); err != nil {
+		return nil, err
+	}
+
+	// GroupBy adds the aggregated columns in map order, so they are put in the order of outputColumns
+	output := n.output()
+	selected := make([]any, len(output))
+	for i, column := range output {
+		selected[i] = column
+	}
+	df = aggregate.GroupBy(df, n.by, n.aggregations).Select(selected...)
+	return df, df.Err()
+}
+
+func (n *groupByNode) describe() string {
+	return fmt.Sprintf("GroupBy %v aggregate %v", n.by, n.aggregated())
+}
+
+func (n *groupByNode) inputs() []node { return []node{n.input} }
+
+func (n *groupByNode) withInputs(inputs []node) node {
+	return &groupByNode{input: inputs[0], by: n.by, aggregations: n.aggregations}
+}
+
+// joinNode joins two inputs on columns
+type joinNode struct {
+	left   node
+	right  node
+	on     []string
+	how    string
+	suffix string
+}
+
+// rightNames maps the columns of the right input, except the on columns, to their names in the result
+func (n *joinNode) rightNames(leftColumns, rightColumns []string) map[string]string {
+	names := make(map[string]string)
+	for _, column := range rightColumns {
+		if slices.Contains(n.on, column) {
+			continue
+		}
+		names[column] = column
+		if slices.Contains(leftColumns, column) {
+			names[column] = column + n.suffix
+		}
+	}
+	return names
+}
+
+func (n *joinNode) outputColumns() ([]string, error) {
+	leftColumns, err := n.left.outputColumns()
+	if err != nil {
+		return nil, err
+	}
+	rightColumns, err := n.right.outputColumns()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := slices.Clone(leftColumns)
+	names := n.rightNames(leftColumns, rightColumns)
+	for _, column := range rightColumns {
+		if name, ok := names[column]; ok {
+			columns = append(columns, name)
+		}
+	}
+	return columns, nil
+}
+
+func (n *joinNode) execute() (*dataframe.DataFrame, error) {
+	left, err := n.left.execute()
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.execute()
+	if err != nil {
+		return nil, err
+	}
+	df := left.Join(right, n.on, dataframe.OptionsMap{"how": n.how, "suffix": n.suffix})
+	return df, df.Err()
+}
+
+func (n *joinNode) describe() string { return fmt.Sprintf("Join %s on %v", n.how, n.on) }
+func (n *joinNode) inputs() []node   { return []node{n.left, n.right} }
+
+func (n *joinNode) withInputs(inputs []node) node {
+	return &joinNode{left: inputs[0], right: inputs[1], on: n.on, how: n.how, suffix: n.suffix}
+}
@@ -0,0 +1,139 @@
+package lazy_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"teddy/dataframe"
+	"teddy/dataframe/aggregate"
+	"teddy/dataframe/expr"
+	"teddy/dataframe/lazy"
+	"testing"
+)
+
+// writeFile writes content to a file in a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Error writing file: %v", err)
+	}
+	return path
+}
+
+func TestScanCollect(t *testing.T) {
+	// Tests that an optimized plan gives the expected result
+	orders := writeFile(t, "orders.csv", "id,customer,amount,note\n5,5,10,a\n6,6,25,b\n7,5,40,c\n8,7,5,d\n")
+	customers := writeFile(t, "customers.csv", "customer,name,city\n5,Ann,Paris\n6,Bob,Oslo\n7,Cid,Rome\n")
+
+	df, err := lazy.Scan(orders).
+		Join(lazy.Scan(customers), []string{"customer"}).
+		WithColumns(expr.Col("amount").Mul(2).Alias("double")).
+		Filter(expr.Col("amount").Gt(8).And(expr.Col("city").Ne("Oslo"))).
+		Select("id", "name", "double").
+		Collect()
+	if err != nil {
+		t.Fatalf("Error collecting: %v", err)
+	}
+
+	if !slices.Equal(df.ColumnNames(), []string{"id", "name", "double"}) {
+		t.Errorf("Unexpected columns %v", df.ColumnNames())
+	}
+	if !slices.Equal(df.GetSeries("id").Values(), []any{5, 7}) || !slices.Equal(df.GetSeries("double").Values(), []any{20, 80}) {
+		t.Errorf("Unexpected rows %v", []any{df.GetSeries("id").Values(), df.GetSeries("double").Values()})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	// Tests that projections and filters are pushed into the scans and selects are merged
+	orders := writeFile(t, "orders.csv", "id,customer,amount,note\n1,1,10,a\n")
+	customers := writeFile(t, "customers.csv", "customer,name,city\n1,Ann,Paris\n")
+
+	plan, err := lazy.Scan(orders).
+		Join(lazy.Scan(customers), []string{"customer"}).
+		Filter(expr.Col("amount").Gt(8)).
+		Filter(expr.Col("city").Eq("Paris")).
+		Select("id", "name", "amount").
+		Select("id", "name").
+		Explain()
+	if err != nil {
+		t.Fatalf("Error explaining: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(plan), "\n")
+	expected := []string{
+		`Select [id name]`,
+		`  Join inner on [customer]`,
+		`    Scan ` + orders + ` columns=[id customer amount] filters=[gt(col("amount"), lit(8))]`,
+		`    Scan ` + customers + ` columns=[customer name city] filters=[eq(col("city"), lit("Paris"))]`,
+	}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("Expected plan\n%s\ngot\n%s", strings.Join(expected, "\n"), plan)
+	}
+}
+
+func TestFilterNotPushed(t *testing.T) {
+	// Tests that filters stay above steps that change their meaning
+	df := dataframe.FromRecords([]map[string]any{
+		{"group": "a", "value": 1},
+		{"group": "a", "value": 2},
+		{"group": "b", "value": 5},
+	})
+
+	lf := lazy.From(df).
+		GroupBy([]string{"group"}, map[string]aggregate.Aggregator{"value": aggregate.Sum()}).
+		Filter(expr.Col("value").Gt(2))
+
+	plan, err := lf.Explain()
+	if err != nil {
+		t.Fatalf("Error explaining: %v", err)
+	}
+	if !strings.HasPrefix(plan, "Filter") {
+		t.Errorf("Expected the filter to stay above the group by, got\n%s", plan)
+	}
+
+	result, err := lf.Collect()
+	if err != nil {
+		t.Fatalf("Error collecting: %v", err)
+	}
+	if result.Height() != 2 {
+		t.Errorf("Expected both groups to pass the filter, got %v", result.GetSeries("group").Values())
+	}
+}
+
+func TestGroupByColumnOrder(t *testing.T) {
+	// Tests that the collected columns are in the order of the plan, not of the aggregations map
+	df := dataframe.FromRecords([]map[string]any{
+		{"group": "a", "a": 1, "b": 2, "c": 3, "d": 4, "e": 5},
+		{"group": "b", "a": 6, "b": 7, "c": 8, "d": 9, "e": 10},
+	})
+	aggregations := map[string]aggregate.Aggregator{}
+	for _, column := range []string{"e", "c", "a", "d", "b"} {
+		aggregations[column] = aggregate.Sum()
+	}
+
+	expected := []string{"group", "a", "b", "c", "d", "e"}
+	for range 10 {
+		result, err := lazy.From(df).GroupBy([]string{"group"}, aggregations).Collect()
+		if err != nil {
+			t.Fatalf("Error collecting: %v", err)
+		}
+		if !slices.Equal(result.ColumnNames(), expected) {
+			t.Fatalf("Expected columns %v, got %v", expected, result.ColumnNames())
+		}
+	}
+}
+
+func TestGroupByUnknownColumn(t *testing.T) {
+	// Tests that aggregating a missing column is an error instead of a dropped column
+	df := dataframe.FromRecords([]map[string]any{{"group": "a", "value": 1}})
+
+	lf := lazy.From(df).GroupBy([]string{"group"}, map[string]aggregate.Aggregator{"missing": aggregate.Sum()})
+	if _, err := lf.Explain(); !errors.Is(err, dataframe.ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound from Explain, got %v", err)
+	}
+	if _, err := lf.Collect(); !errors.Is(err, dataframe.ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound from Collect, got %v", err)
+	}
+}
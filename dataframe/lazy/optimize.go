@@ -0,0 +1,241 @@
+package lazy
+
+import (
+	"slices"
+	"teddy/dataframe/expr"
+)
+
+// optimize returns a plan that gives the same result as plan while reading and processing less data
+func optimize(plan node) (node, error) {
+	plan, err := pushFilters(plan)
+	if err != nil {
+		return nil, err
+	}
+	plan, err = pushProjections(plan, nil)
+	if err != nil {
+		return nil, err
+	}
+	return mergeSelects(plan), nil
+}
+
+// pushFilters moves every filter of the plan as close to the data source as possible
+func pushFilters(n node) (node, error) {
+	inputs := make([]node, len(n.inputs()))
+	for i, input := range n.inputs() {
+		pushed, err := pushFilters(input)
+		if err != nil {
+			return nil, err
+		}
+		inputs[i] = pushed
+	}
+	n = n.withInputs(inputs)
+
+	if filter, ok := n.(*filterNode); ok {
+		return pushFilter(filter.predicate, filter.input)
+	}
+	return n, nil
+}
+
+// pushFilter adds a filter with the predicate to n, below n if the result stays the same
+func pushFilter(predicate *expr.Expr, n node) (node, error) {
+	columns := predicate.Columns()
+	// pushInto pushes the filter into the input of n at the index
+	pushInto := func(index int) (node, error) {
+		inputs := n.inputs()
+		pushed, err := pushFilter(predicate, inputs[index])
+		if err != nil {
+			return nil, err
+		}
+		inputs[index] = pushed
+		return n.withInputs(inputs), nil
+	}
+
+	switch n := n.(type) {
+	case *scanNode:
+		scan := *n
+		scan.filters = append(slices.Clone(n.filters), predicate)
+		return &scan, nil
+	case *selectNode:
+		if containsAll(n.columns, columns) {
+			return pushInto(0)
+		}
+	case *filterNode:
+		return pushInto(0)
+	case *withColumnsNode:
+		produced := false
+		for _, e := range n.exprs {
+			produced = produced || slices.Contains(columns, e.Name())
+		}
+		if !produced {
+			return pushInto(0)
+		}
+	case *groupByNode:
+		if containsAll(n.by, columns) {
+			return pushInto(0)
+		}
+	case *joinNode:
+		leftColumns, err := n.left.outputColumns()
+		if err != nil {
+			return nil, err
+		}
+		if containsAll(leftColumns, columns) {
+			return pushInto(0)
+		}
+
+		// Filtering the right input of a left join would keep the rows with nulls instead of dropping them
+		rightColumns, err := n.right.outputColumns()
+		if err != nil {
+			return nil, err
+		}
+		names := n.rightNames(leftColumns, rightColumns)
+		onRight := func(column string) bool { return slices.Contains(n.on, column) || names[column] == column }
+		if n.how == "inner" && !slices.ContainsFunc(columns, func(column string) bool { return !onRight(column) }) {
+			return pushInto(1)
+		}
+	}
+	return &filterNode{input: n, predicate: predicate}, nil
+}
+
+// pushProjections makes the data sources read only the columns needed for the required columns of n.
+// A nil required needs all columns.
+func pushProjections(n node, required []string) (node, error) {
+	var inputRequired [][]string
+	switch n := n.(type) {
+	case *scanNode:
+		if required == nil {
+			return n, nil
+		}
+		header, err := n.outputColumns()
+		if err != nil {
+			return nil, err
+		}
+		needed := slices.Clone(required)
+		for _, filter := range n.filters {
+			needed = union(needed, filter.Columns())
+		}
+
+		scan := *n
+		scan.columns = []string{}
+		for _, column := range header {
+			if slices.Contains(needed, column) {
+				scan.columns = append(scan.columns, column)
+			}
+		}
+		return &scan, nil
+	case *selectNode:
+		inputRequired = [][]string{n.columns}
+	case *filterNode:
+		if required != nil {
+			required = union(required, n.predicate.Columns())
+		}
+		inputRequired = [][]string{required}
+	case *withColumnsNode:
+		var needed []string
+		if required != nil {
+			needed = []string{}
+			for _, column := range required {
+				if !slices.ContainsFunc(n.exprs, func(e *expr.Expr) bool { return e.Name() == column }) {
+					needed = append(needed, column)
+				}
+			}
+			for _, e := range n.exprs {
+				needed = union(needed, e.Columns())
+			}
+		}
+		inputRequired = [][]string{needed}
+	case *groupByNode:
+		// Unknown columns are reported here, since they are skipped when executing
+		if _, err := n.outputColumns(); err != nil {
+			return nil, err
+		}
+		needed := slices.Clone(n.by)
+		for _, column := range n.aggregated() {
+			// count doesn't read a column
+			if column != "count" {
+				needed = union(needed, []string{column})
+			}
+		}
+		inputRequired = [][]string{needed}
+	case *joinNode:
+		if required == nil {
+			inputRequired = [][]string{nil, nil}
+			break
+		}
+
+		leftColumns, err := n.left.outputColumns()
+		if err != nil {
+			return nil, err
+		}
+		rightColumns, err := n.right.outputColumns()
+		if err != nil {
+			return nil, err
+		}
+
+		leftRequired := slices.Clone(n.on)
+		rightRequired := slices.Clone(n.on)
+		for _, column := range leftColumns {
+			if slices.Contains(required, column) {
+				leftRequired = union(leftRequired, []string{column})
+			}
+		}
+		for column, name := range n.rightNames(leftColumns, rightColumns) {
+			if slices.Contains(required, name) {
+				rightRequired = union(rightRequired, []string{column})
+				// Keep the left column so the right one keeps its suffix
+				if name != column {
+					leftRequired = union(leftRequired, []string{column})
+				}
+			}
+		}
+		inputRequired = [][]string{leftRequired, rightRequired}
+	default:
+		return n, nil
+	}
+
+	inputs := make([]node, len(n.inputs()))
+	for i, input := range n.inputs() {
+		pushed, err := pushProjections(input, inputRequired[i])
+		if err != nil {
+			return nil, err
+		}
+		inputs[i] = pushed
+	}
+	return n.withInputs(inputs), nil
+}
+
+// mergeSelects replaces a select of a select with a single select
+func mergeSelects(n node) node {
+	inputs := make([]node, len(n.inputs()))
+	for i, input := range n.inputs() {
+		inputs[i] = mergeSelects(input)
+	}
+	n = n.withInputs(inputs)
+
+	if outer, ok := n.(*selectNode); ok {
+		if inner, ok := outer.input.(*selectNode); ok && containsAll(inner.columns, outer.columns) {
+			return &selectNode{input: inner.input, columns: outer.columns}
+		}
+	}
+	return n
+}
+
+// containsAll returns true if all values are in s
+func containsAll(s []string, values []string) bool {
+	for _, value := range values {
+		if !slices.Contains(s, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// union returns s with the values that are not already in it appended
+func union(s []string, values []string) []string {
+	result := slices.Clone(s)
+	for _, value := range values {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
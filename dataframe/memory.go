@@ -0,0 +1,71 @@
+package dataframe
+
+import (
+	"fmt"
+	"reflect"
+	"teddy/dataframe/series"
+)
+
+// MemoryUsage returns a DataFrame with the number of bytes used by each column.
+//
+// The result has a row per column with "column", "type" and "bytes". Without deep,
+// only the slices of the columns are counted. With deep, the bytes of strings and
+// of the values boxed in a GenericSeries are counted too, and two more columns help
+// decide whether a column is worth converting to a categorical:
+//   - unique: the number of distinct non-null values.
+//   - dictionary_bytes: the estimated bytes as a dictionary of the distinct values
+//     plus a 4-byte code for each row.
+func (df *DataFrame) MemoryUsage(deep bool) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	names := make([]string, df.Width())
+	types := make([]string, df.Width())
+	bytes := make([]int, df.Width())
+	uniques := make([]int, df.Width())
+	dictionaries := make([]int, df.Width())
+	for i, s := range df.series {
+		names[i], types[i] = s.Name(), seriesTypeName(s)
+		bytes[i] = int(series.MemoryUsage(s, deep))
+		if deep {
+			distinct := distinctValues(s)
+			uniques[i] = len(distinct)
+			dictionaries[i] = int(series.MemoryUsage(series.NewSeries(s.Name(), distinct), true)) + 4*s.Len()
+		}
+	}
+
+	result := NewDataFrame(
+		series.NewStringSeries("column", names),
+		series.NewStringSeries("type", types),
+		series.NewIntSeries("bytes", bytes),
+	)
+	if deep {
+		result = result.AddSeries(series.NewIntSeries("unique", uniques))
+		result = result.AddSeries(series.NewIntSeries("dictionary_bytes", dictionaries))
+	}
+	return result
+}
+
+// distinctValues returns the distinct non-null values of a Series in order of first appearance
+func distinctValues(s series.SeriesInterface) []any {
+	seen := make(map[any]bool)
+	values := []any{}
+	for i := range s.Len() {
+		v := s.Get(i)
+		if v == nil {
+			continue
+		}
+
+		// Values that can't be map keys, such as slices, are compared by their representation
+		var key any = v
+		if !reflect.TypeOf(v).Comparable() {
+			key = fmt.Sprintf("%#v", v)
+		}
+		if !seen[key] {
+			seen[key] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -7,6 +7,10 @@ type Options struct {
 	trimleadingspace bool
 	header           bool
 	inferdatatypes   bool
+	schema           *Schema
+	columns          []string
+	inferrows        int
+	coerce           bool
 }
 
 func NewOptions() *Options {
@@ -47,6 +51,26 @@ func (options *Options) SetInferDataTypes(inferDataTypes bool) *Options {
 	return options
 }
 
+func (options *Options) SetSchema(schema *Schema) *Options {
+	options.schema = schema
+	return options
+}
+
+func (options *Options) SetColumns(columns []string) *Options {
+	options.columns = columns
+	return options
+}
+
+func (options *Options) SetInferRows(inferRows int) *Options {
+	options.inferrows = inferRows
+	return options
+}
+
+func (options *Options) SetCoerce(coerce bool) *Options {
+	options.coerce = coerce
+	return options
+}
+
 func (options *Options) GetDelimiter() rune {
 	return options.delimiter
 }
@@ -62,3 +86,19 @@ func (options *Options) GetHeader() bool {
 func (options *Options) GetInferDataTypes() bool {
 	return options.inferdatatypes
 }
+
+func (options *Options) GetSchema() *Schema {
+	return options.schema
+}
+
+func (options *Options) GetColumns() []string {
+	return options.columns
+}
+
+func (options *Options) GetInferRows() int {
+	return options.inferrows
+}
+
+func (options *Options) GetCoerce() bool {
+	return options.coerce
+}
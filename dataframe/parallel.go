@@ -0,0 +1,63 @@
+package dataframe
+
+import (
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"teddy/dataframe/internal/parallel"
+)
+
+// defaultParallelism is the number of goroutines used when a DataFrame doesn't set its own
+var defaultParallelism atomic.Int64
+
+// SetDefaultParallelism sets the number of goroutines ApplyIndex, ApplyMap, FilterIndex,
+// FilterMap and aggregate.GroupBy split the rows across. A value below 1 uses all CPUs.
+//
+// The default is 1, which runs on the calling goroutine. With more, the functions passed
+// to these operations must be safe to call concurrently. Results are always in row order.
+func SetDefaultParallelism(n int) {
+	if n < 1 {
+		n = runtime.NumCPU()
+	}
+	defaultParallelism.Store(int64(n))
+}
+
+// DefaultParallelism returns the number of goroutines set by SetDefaultParallelism
+func DefaultParallelism() int {
+	return max(1, int(defaultParallelism.Load()))
+}
+
+// SetParallelism returns the DataFrame with the number of goroutines its operations use,
+// instead of the default. A value below 1 uses the default again.
+// The DataFrame is not changed unless it is in place mode, and DataFrames returned
+// by its operations keep the setting.
+//
+//	df.SetParallelism(8).FilterMap(f)
+func (df *DataFrame) SetParallelism(n int) *DataFrame {
+	result := df.target()
+	result.parallelism = max(0, n)
+	return result
+}
+
+// Parallelism returns the number of goroutines operations on the DataFrame use
+func (df *DataFrame) Parallelism() int {
+	if df.parallelism > 0 {
+		return df.parallelism
+	}
+	return DefaultParallelism()
+}
+
+// rowsWhere returns the rows where the predicate is true, in order.
+// The rows are split across the goroutines set by Parallelism.
+func (df *DataFrame) rowsWhere(predicate func(row int) bool) []int {
+	ranges := parallel.Ranges(df.Height(), df.Parallelism())
+	matches := make([][]int, len(ranges))
+	parallel.Run(ranges, func(i, start, end int) {
+		for row := start; row < end; row++ {
+			if predicate(row) {
+				matches[i] = append(matches[i], row)
+			}
+		}
+	})
+	return slices.Concat(matches...)
+}
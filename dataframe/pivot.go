@@ -0,0 +1,148 @@
+package dataframe
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"teddy/dataframe/series"
+)
+
+// Pivot reshapes the DataFrame from long to wide format.
+//
+// Each unique combination of the index columns becomes a row and each unique value
+// of the columns column becomes a new column holding the matching value.
+// New columns are sorted by their label. Rows keep the order they first appear in.
+// Returns an error if a cell would receive more than one value,
+// use aggregate.PivotTable to combine duplicates instead.
+//
+// Options:
+//   - fill_value: any (default: nil) The value for cells without a matching row.
+func (df *DataFrame) Pivot(index []string, columns string, values string, options ...OptionsMap) (*DataFrame, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	optionsClean := standardizeOptions(options...)
+	fillValue := optionsClean.getOption("fill_value", nil)
+
+	required := append(slices.Clone(index), columns, values)
+	if missing := df.findColumnsThatDontExist(required); len(missing) > 0 {
+		return nil, columnNotFound(missing...)
+	}
+
+	indexSeries := make([]series.SeriesInterface, len(index))
+	for i, col := range index {
+		indexSeries[i] = df.GetSeries(col)
+	}
+	columnSeries := df.GetSeries(columns)
+	valueSeries := df.GetSeries(values)
+
+	// Find the unique rows and column labels in order of first appearance
+	rowPositions := make(map[string]int)
+	firstRows := []int{}
+	labelPositions := make(map[string]int)
+	labels := []any{}
+	for i := 0; i < df.Height(); i++ {
+		key := rowKey(indexSeries, i)
+		if _, ok := rowPositions[key]; !ok {
+			rowPositions[key] = len(firstRows)
+			firstRows = append(firstRows, i)
+		}
+
+		label := columnSeries.Get(i)
+		labelKey := fmt.Sprint(label)
+		if _, ok := labelPositions[labelKey]; !ok {
+			labelPositions[labelKey] = len(labels)
+			labels = append(labels, label)
+		}
+	}
+
+	// Fill the cells, failing on duplicates
+	cells := make([][]any, len(labels))
+	filled := make([][]bool, len(labels))
+	for i := range labels {
+		cells[i] = make([]any, len(firstRows))
+		filled[i] = make([]bool, len(firstRows))
+		for j := range cells[i] {
+			cells[i][j] = fillValue
+		}
+	}
+	for i := 0; i < df.Height(); i++ {
+		row := rowPositions[rowKey(indexSeries, i)]
+		col := labelPositions[fmt.Sprint(columnSeries.Get(i))]
+		if filled[col][row] {
+			label := make([]any, len(indexSeries))
+			for j, s := range indexSeries {
+				label[j] = s.Get(i)
+			}
+			return nil, fmt.Errorf("Duplicate entry for index %v and column %v", label, columnSeries.Get(i))
+		}
+		cells[col][row] = valueSeries.Get(i)
+		filled[col][row] = true
+	}
+
+	result := NewDataFrame()
+	for i, s := range indexSeries {
+		indexValues := make([]any, len(firstRows))
+		for j, row := range firstRows {
+			indexValues[j] = s.Get(row)
+		}
+		result.series = append(result.series, series.NewSeries(index[i], indexValues))
+	}
+
+	for _, label := range sortLabels(labels) {
+		name := fmt.Sprint(label)
+		if result.HasColumn(name) {
+			return nil, errors.New("Pivoted column name already exists: " + name)
+		}
+		result.series = append(result.series, series.NewSeries(name, cells[labelPositions[name]]))
+	}
+
+	return result, nil
+}
+
+// rowKey builds a composite key from the values of the given series at a row
+func rowKey(keySeries []series.SeriesInterface, row int) string {
+	values := make([]any, len(keySeries))
+	for i, s := range keySeries {
+		if !s.IsNull(row) {
+			values[i] = s.Get(row)
+		}
+	}
+	return valuesKey(values)
+}
+
+// valuesKey builds a composite key from values. Each value is written with its type and
+// length, so different values never have the same key, and a null never has the key of a value.
+func valuesKey(values []any) string {
+	var builder strings.Builder
+	for _, value := range values {
+		text := fmt.Sprint(value)
+		fmt.Fprintf(&builder, "%T:%d:%s|", value, len(text), text)
+	}
+	return builder.String()
+}
+
+// sortLabels returns the labels sorted numerically if they are all numbers, otherwise as strings
+func sortLabels(labels []any) []any {
+	numbers, numeric := series.ToFloat64Slice(labels)
+
+	order := make([]int, len(labels))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if numeric {
+			return cmp.Compare(numbers[a], numbers[b])
+		}
+		return cmp.Compare(fmt.Sprint(labels[a]), fmt.Sprint(labels[b]))
+	})
+
+	sorted := make([]any, len(labels))
+	for i, position := range order {
+		sorted[i] = labels[position]
+	}
+	return sorted
+}
@@ -191,7 +191,11 @@ func (df *DataFrame) Summary() {
 		}
 
 		// Show type-specific information
-		switch s := seriess.(type) {
+		compacted := seriess
+		if chunked, ok := seriess.(*series.ChunkedSeries); ok {
+			compacted = chunked.Compact()
+		}
+		switch s := compacted.(type) {
 		case *series.IntSeries:
 			if len(s.Values()) > 0 {
 				values, _ := series.ToIntSlice(s.Values())
@@ -226,26 +230,7 @@ func (df *DataFrame) Summary() {
 	totalBytes := int64(0)
 
 	for _, seriess := range df.series {
-		seriesBytes := int64(0)
-		switch s := seriess.(type) {
-		case *series.IntSeries:
-			seriesBytes = int64(s.Len() * 8) // 8 bytes per int
-		case *series.Float64Series:
-			seriesBytes = int64(s.Len() * 8) // 8 bytes per float64
-		case *series.BoolSeries:
-			seriesBytes = int64(s.Len() * 1) // 1 byte per bool
-		case *series.StringSeries:
-			// Estimate string size (rough approximation)
-			stringSize := int64(0)
-			for _, str := range series.ToStringSlice(s.Values()) {
-				stringSize += int64(len(str))
-			}
-			seriesBytes = stringSize + int64(s.Len()*16) // String data + overhead
-		case *series.GenericSeries:
-			// Generic series is hard to estimate precisely
-			seriesBytes = int64(s.Len() * 16) // Pointer size + type info
-		}
-
+		seriesBytes := series.MemoryUsage(seriess, true)
 		fmt.Printf("  %s: ~%s\n", seriess.Name(), formatBytes(seriesBytes))
 		totalBytes += seriesBytes
 	}
@@ -0,0 +1,77 @@
+package dataframe
+
+import (
+	"fmt"
+	"slices"
+	"teddy/dataframe/series"
+)
+
+// FromRecords creates a DataFrame from a slice of maps of column name to value.
+//
+// The columns are the union of the keys of all records. Missing keys and nil values
+// become nulls. The type of each column is inferred with series.NewSeries.
+// Since maps have no order, the columns are sorted by name unless an order is given.
+// Sets an error if the columns option is not a []string or lists a column twice.
+//
+// Options:
+//   - columns: []string (default: nil) The order of the columns. Keys that are not
+//     listed are added after them, sorted by name.
+func FromRecords(records []map[string]any, options ...OptionsMap) *DataFrame {
+	optionsClean := standardizeOptions(options...)
+	order, ok := optionsClean.getOption("columns", []string{}).([]string)
+	if !ok {
+		return NewDataFrame().withError(fmt.Errorf("The columns option must be a []string, got %T", optionsClean["columns"]))
+	}
+
+	columns := slices.Clone(order)
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if seen[column] {
+			return NewDataFrame().withError(fmt.Errorf("Duplicate column name in the columns option: %s", column))
+		}
+		seen[column] = true
+	}
+
+	// Discover the keys that are not in the given order
+	extraColumns := []string{}
+	for _, record := range records {
+		for key := range record {
+			if !seen[key] {
+				seen[key] = true
+				extraColumns = append(extraColumns, key)
+			}
+		}
+	}
+	slices.Sort(extraColumns)
+	columns = append(columns, extraColumns...)
+
+	df := NewDataFrame()
+	for _, column := range columns {
+		values := make([]any, len(records))
+		for i, record := range records {
+			values[i] = record[column]
+		}
+		df.series = append(df.series, series.NewSeries(column, values))
+	}
+
+	return df
+}
+
+// ToRecords returns the rows of the DataFrame as a slice of maps of column name to value.
+//
+// Null values are included as nil. Returns the error of the DataFrame if it has one.
+func (df *DataFrame) ToRecords() ([]map[string]any, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	records := make([]map[string]any, df.Height())
+	for i := range records {
+		record := make(map[string]any, len(df.series))
+		for _, s := range df.series {
+			record[s.Name()] = s.Get(i)
+		}
+		records[i] = record
+	}
+	return records, nil
+}
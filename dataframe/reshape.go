@@ -0,0 +1,248 @@
+package dataframe
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"teddy/dataframe/series"
+)
+
+// Melt reshapes the DataFrame from wide to long format.
+//
+// The idVars columns are repeated for each of the valueVars columns. The name of
+// the valueVars column is stored in varName and its value in valueName.
+// If valueVars is empty, all columns that are not idVars are used.
+// Value columns of different types are promoted to a common type (int + float -> float).
+func (df *DataFrame) Melt(idVars, valueVars []string, varName, valueName string) (*DataFrame, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	if len(valueVars) == 0 {
+		for _, name := range df.ColumnNames() {
+			if !slices.Contains(idVars, name) {
+				valueVars = append(valueVars, name)
+			}
+		}
+	}
+
+	if missing := df.findColumnsThatDontExist(append(slices.Clone(idVars), valueVars...)); len(missing) > 0 {
+		return nil, columnNotFound(missing...)
+	}
+	if slices.Contains(idVars, varName) || slices.Contains(idVars, valueName) || varName == valueName {
+		return nil, errors.New("varName and valueName must be unique column names")
+	}
+
+	height := df.Height()
+	heights := make([]int, len(valueVars))
+	for i := range heights {
+		heights[i] = height
+	}
+
+	result := NewDataFrame()
+
+	// Repeat the id columns once for each value column
+	for _, name := range idVars {
+		parts := make([]series.SeriesInterface, len(valueVars))
+		for i := range parts {
+			parts[i] = df.GetSeries(name)
+		}
+		result.series = append(result.series, concatSeries(name, parts, heights))
+	}
+
+	// The name of the value column for each row
+	names := make([]string, 0, height*len(valueVars))
+	for _, name := range valueVars {
+		for i := 0; i < height; i++ {
+			names = append(names, name)
+		}
+	}
+	result.series = append(result.series, series.NewStringSeries(varName, names))
+
+	// Stack the value columns
+	parts := make([]series.SeriesInterface, len(valueVars))
+	for i, name := range valueVars {
+		parts[i] = df.GetSeries(name)
+	}
+	result.series = append(result.series, concatSeries(valueName, parts, heights))
+
+	return result, nil
+}
+
+// Transpose returns a new DataFrame where the rows are columns and the columns are rows.
+//
+// The first column holds the original column names. The other columns are named
+// "Column 0", "Column 1", ... unless a header column is given.
+// All values are promoted to a common type (int + float -> float). If there is no
+// common type, the columns are GenericSeries.
+//
+// Options:
+//   - header_column: string (default: "") A column whose values are used as the new column names.
+//     The header column itself is not transposed.
+func (df *DataFrame) Transpose(options ...OptionsMap) (*DataFrame, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	optionsClean := standardizeOptions(options...)
+	headerColumn := optionsClean.getOption("header_column", "").(string)
+
+	// Columns that become rows
+	dataSeries := df.series
+	names := make([]string, df.Height())
+	for i := range names {
+		names[i] = fmt.Sprintf("Column %d", i)
+	}
+	firstName := "Column"
+
+	if headerColumn != "" {
+		header := df.GetSeries(headerColumn)
+		if header == nil {
+			return nil, columnNotFound(headerColumn)
+		}
+		for i := range names {
+			names[i] = fmt.Sprint(header.Get(i))
+		}
+		firstName = headerColumn
+		dataSeries = slices.DeleteFunc(slices.Clone(df.series), func(s series.SeriesInterface) bool {
+			return s.Name() == headerColumn
+		})
+	}
+
+	columnNames := make([]string, len(dataSeries))
+	for i, s := range dataSeries {
+		columnNames[i] = s.Name()
+	}
+
+	result := NewDataFrame(series.NewStringSeries(firstName, columnNames))
+	typ := promoteType(dataSeries)
+	for i, name := range names {
+		if result.HasColumn(name) {
+			return nil, errors.New("Duplicate column name: " + name)
+		}
+
+		values := make([]any, len(dataSeries))
+		for j, s := range dataSeries {
+			values[j] = s.Get(i)
+		}
+		result.series = append(result.series, newSeriesOfType(name, typ, values))
+	}
+
+	return result, nil
+}
+
+// Explode returns a new DataFrame with one row for each element of the column.
+//
+// String values are split on the separator. Values that are []string or []any are
+// exploded element by element. The other columns are repeated and keep their types.
+// The rows stay in their original order.
+func (df *DataFrame) Explode(column string, separator string) (*DataFrame, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	s := df.GetSeries(column)
+	if s == nil {
+		return nil, columnNotFound(column)
+	}
+
+	rows := []int{}
+	elements := []any{}
+	for i := 0; i < s.Len(); i++ {
+		switch value := s.Get(i).(type) {
+		case string:
+			for _, element := range strings.Split(value, separator) {
+				rows = append(rows, i)
+				elements = append(elements, element)
+			}
+		case []string:
+			for _, element := range value {
+				rows = append(rows, i)
+				elements = append(elements, element)
+			}
+		case []any:
+			for _, element := range value {
+				rows = append(rows, i)
+				elements = append(elements, element)
+			}
+		default:
+			rows = append(rows, i)
+			elements = append(elements, value)
+		}
+	}
+
+	result := NewDataFrame()
+	for _, s := range df.series {
+		if s.Name() == column {
+			result.series = append(result.series, series.NewSeries(column, elements))
+		} else {
+			result.series = append(result.series, takeRows(s, rows))
+		}
+	}
+
+	return result, nil
+}
+
+// Implode is the inverse of Explode. It groups the rows by the given columns and
+// joins the values of the column with the separator.
+//
+// The groups are in order of first appearance. Null values are skipped.
+func (df *DataFrame) Implode(by []string, column string, separator string) (*DataFrame, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	if missing := df.findColumnsThatDontExist(append(slices.Clone(by), column)); len(missing) > 0 {
+		return nil, columnNotFound(missing...)
+	}
+
+	keySeries := make([]series.SeriesInterface, len(by))
+	for i, col := range by {
+		keySeries[i] = df.GetSeries(col)
+	}
+	s := df.GetSeries(column)
+
+	// Group the values in order of first appearance
+	groupPositions := make(map[string]int)
+	firstRows := []int{}
+	groups := [][]string{}
+	for i := 0; i < df.Height(); i++ {
+		key := rowKey(keySeries, i)
+		position, ok := groupPositions[key]
+		if !ok {
+			position = len(firstRows)
+			groupPositions[key] = position
+			firstRows = append(firstRows, i)
+			groups = append(groups, []string{})
+		}
+		if !s.IsNull(i) {
+			groups[position] = append(groups[position], fmt.Sprint(s.Get(i)))
+		}
+	}
+
+	result := NewDataFrame()
+	for _, s := range keySeries {
+		result.series = append(result.series, takeRows(s, firstRows))
+	}
+
+	joined := make([]string, len(groups))
+	for i, group := range groups {
+		joined[i] = strings.Join(group, separator)
+	}
+	result.series = append(result.series, series.NewStringSeries(column, joined))
+
+	return result, nil
+}
+
+// takeRows returns a new Series with the values at the given rows, keeping the type of s.
+// A negative row gives a null value.
+func takeRows(s series.SeriesInterface, rows []int) series.SeriesInterface {
+	values := make([]any, len(rows))
+	for i, row := range rows {
+		if row >= 0 {
+			values[i] = s.Get(row)
+		}
+	}
+	return newSeriesOfType(s.Name(), promoteType([]series.SeriesInterface{s}), values)
+}
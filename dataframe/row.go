@@ -0,0 +1,132 @@
+package dataframe
+
+import (
+	"iter"
+	"teddy/dataframe/series"
+
+	convert "teddy/dataframe/convert"
+)
+
+// Row is a view of a single row of a DataFrame
+type Row struct {
+	df      *DataFrame
+	columns map[string]int
+	index   int
+}
+
+// Rows returns an iterator over the index and a view of each row.
+func (df *DataFrame) Rows() iter.Seq2[int, Row] {
+	return func(yield func(int, Row) bool) {
+		columns := make(map[string]int, len(df.series))
+		for i, s := range df.series {
+			if _, ok := columns[s.Name()]; !ok {
+				columns[s.Name()] = i
+			}
+		}
+
+		for i := 0; i < df.Height(); i++ {
+			if !yield(i, Row{df: df, columns: columns, index: i}) {
+				return
+			}
+		}
+	}
+}
+
+// Index returns the position of the row in the DataFrame
+func (r Row) Index() int {
+	return r.index
+}
+
+// Get returns the value of the column, or nil if the column doesn't exist
+func (r Row) Get(columnName string) any {
+	s, i := r.series(columnName)
+	if s == nil {
+		return nil
+	}
+	return s.Get(i)
+}
+
+// IsNull returns true if the value of the column is null or the column doesn't exist
+func (r Row) IsNull(columnName string) bool {
+	s, i := r.series(columnName)
+	return s == nil || s.IsNull(i)
+}
+
+// Int returns the value of the column as an int.
+//
+// Returns 0 if the column doesn't exist or the value can't be converted.
+func (r Row) Int(columnName string) int {
+	s, i := r.series(columnName)
+	if s == nil {
+		return 0
+	}
+	if intSeries, ok := s.(*series.IntSeries); ok {
+		return intSeries.Value(i)
+	}
+	value, err := convert.ConvertValue(s.Get(i), "int")
+	if err != nil {
+		return 0
+	}
+	return value.(int)
+}
+
+// Float returns the value of the column as a float64.
+//
+// Returns 0 if the column doesn't exist or the value can't be converted.
+func (r Row) Float(columnName string) float64 {
+	s, i := r.series(columnName)
+	if s == nil {
+		return 0
+	}
+	if floatSeries, ok := s.(*series.Float64Series); ok {
+		return floatSeries.Value(i)
+	}
+	value, err := convert.ConvertValue(s.Get(i), "float")
+	if err != nil {
+		return 0
+	}
+	return value.(float64)
+}
+
+// String returns the value of the column as a string.
+//
+// Returns "" if the column doesn't exist or the value is null.
+func (r Row) String(columnName string) string {
+	s, i := r.series(columnName)
+	if s == nil {
+		return ""
+	}
+	if stringSeries, ok := s.(*series.StringSeries); ok {
+		return stringSeries.Value(i)
+	}
+	return convert.ConvertToString(s.Get(i))
+}
+
+// Bool returns the value of the column as a bool.
+//
+// Returns false if the column doesn't exist or the value can't be converted.
+func (r Row) Bool(columnName string) bool {
+	s, i := r.series(columnName)
+	if s == nil {
+		return false
+	}
+	if boolSeries, ok := s.(*series.BoolSeries); ok {
+		return boolSeries.Value(i)
+	}
+	return convert.ConvertToBool(s.Get(i))
+}
+
+// series returns the Series of the column and the position of the row in it, or nil if the
+// column doesn't exist. For a ChunkedSeries it is the chunk holding the row, so the typed
+// getters can read it directly.
+func (r Row) series(columnName string) (series.SeriesInterface, int) {
+	column, ok := r.columns[columnName]
+	if !ok {
+		return nil, 0
+	}
+	s := r.df.series[column]
+	if chunked, ok := s.(*series.ChunkedSeries); ok {
+		return chunked.Locate(r.index)
+	}
+	return s, r.index
+}
@@ -0,0 +1,183 @@
+package dataframe
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"teddy/dataframe/series"
+)
+
+// Sample returns a new DataFrame with randomly selected rows.
+//
+// Options:
+//   - n: int (default: 0) The number of rows to select.
+//   - fraction: float64 (default: 0) The fraction of rows to select. Used when n is not set.
+//   - replace: bool (default: false) If true, rows can be selected more than once.
+//   - seed: int (default: random) The seed for reproducible samples.
+func (df *DataFrame) Sample(options ...OptionsMap) (*DataFrame, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	optionsClean := standardizeOptions(options...)
+	n := optionsClean.getOption("n", 0).(int)
+	fraction := optionsClean.getOption("fraction", 0.0).(float64)
+	replace := optionsClean.getOption("replace", false).(bool)
+	rng := newRand(optionsClean)
+
+	if _, ok := optionsClean["n"]; !ok {
+		n = int(fraction * float64(df.Height()))
+	}
+
+	rows, err := sampleRows(rng, allRows(df.Height()), n, replace)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(rows)
+
+	return df.takeRows(rows), nil
+}
+
+// StratifiedSample returns a new DataFrame with the same fraction of rows selected from each group.
+//
+// The groups are the unique combinations of the by columns.
+//
+// Options:
+//   - seed: int (default: random) The seed for reproducible samples.
+func (df *DataFrame) StratifiedSample(by []string, fraction float64, options ...OptionsMap) (*DataFrame, error) {
+	if df.err != nil {
+		return nil, df.err
+	}
+
+	optionsClean := standardizeOptions(options...)
+	rng := newRand(optionsClean)
+
+	if missing := df.findColumnsThatDontExist(by); len(missing) > 0 {
+		return nil, columnNotFound(missing...)
+	}
+
+	keySeries := make([]series.SeriesInterface, len(by))
+	for i, col := range by {
+		keySeries[i] = df.GetSeries(col)
+	}
+
+	// Group the rows in order of first appearance
+	groupPositions := make(map[string]int)
+	groups := [][]int{}
+	for i := 0; i < df.Height(); i++ {
+		key := rowKey(keySeries, i)
+		position, ok := groupPositions[key]
+		if !ok {
+			position = len(groups)
+			groupPositions[key] = position
+			groups = append(groups, []int{})
+		}
+		groups[position] = append(groups[position], i)
+	}
+
+	rows := []int{}
+	for _, group := range groups {
+		sampled, err := sampleRows(rng, group, int(fraction*float64(len(group))), false)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, sampled...)
+	}
+	slices.Sort(rows)
+
+	return df.takeRows(rows), nil
+}
+
+// TrainTestSplit randomly splits the rows into a train and a test DataFrame.
+//
+// testFraction is the fraction of rows in the test DataFrame.
+//
+// Options:
+//   - seed: int (default: random) The seed for reproducible splits.
+func (df *DataFrame) TrainTestSplit(testFraction float64, options ...OptionsMap) (*DataFrame, *DataFrame, error) {
+	if df.err != nil {
+		return nil, nil, df.err
+	}
+
+	optionsClean := standardizeOptions(options...)
+	rng := newRand(optionsClean)
+
+	if testFraction < 0 || testFraction > 1 {
+		return nil, nil, errors.New("testFraction must be between 0 and 1")
+	}
+
+	rows := allRows(df.Height())
+	rng.Shuffle(len(rows), func(i, j int) { rows[i], rows[j] = rows[j], rows[i] })
+
+	testSize := int(testFraction * float64(len(rows)))
+	testRows := slices.Clone(rows[:testSize])
+	trainRows := slices.Clone(rows[testSize:])
+	slices.Sort(testRows)
+	slices.Sort(trainRows)
+
+	return df.takeRows(trainRows), df.takeRows(testRows), nil
+}
+
+// newRand returns a random number generator seeded with the seed option, or a random seed if it isn't set
+func newRand(options OptionsMap) *rand.Rand {
+	var seed int64
+	switch value := options.getOption("seed", nil).(type) {
+	case int:
+		seed = int64(value)
+	case int64:
+		seed = value
+	default:
+		seed = rand.Int63()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// sampleRows selects n of the rows at random
+func sampleRows(rng *rand.Rand, rows []int, n int, replace bool) ([]int, error) {
+	if n < 0 {
+		return nil, errors.New("Sample size must not be negative")
+	}
+
+	if replace {
+		if len(rows) == 0 && n > 0 {
+			return nil, errors.New("Cannot sample from an empty DataFrame")
+		}
+		sampled := make([]int, n)
+		for i := range sampled {
+			sampled[i] = rows[rng.Intn(len(rows))]
+		}
+		return sampled, nil
+	}
+
+	if n > len(rows) {
+		return nil, errors.New("Sample size is larger than the number of rows, use replace to sample with replacement")
+	}
+
+	// Partial Fisher-Yates shuffle of the first n positions
+	shuffled := slices.Clone(rows)
+	for i := 0; i < n; i++ {
+		j := i + rng.Intn(len(shuffled)-i)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled[:n], nil
+}
+
+// allRows returns the row indexes 0 to height-1
+func allRows(height int) []int {
+	rows := make([]int, height)
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
+
+// takeRows returns a new DataFrame with the given rows, keeping the column types
+func (df *DataFrame) takeRows(rows []int) *DataFrame {
+	result := NewDataFrame()
+	for _, s := range df.series {
+		result.series = append(result.series, takeRows(s, rows))
+	}
+	result.index = df.index.takeRows(rows)
+	result.parallelism = df.parallelism
+	return result
+}
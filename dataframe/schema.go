@@ -0,0 +1,275 @@
+package dataframe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"teddy/dataframe/series"
+
+	convert "teddy/dataframe/convert"
+)
+
+// Field describes a column of a Schema
+type Field struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// Schema describes the names, types and nullability of the columns of a DataFrame, in order.
+//
+// Types are "int", "float64", "string", "bool", "time" or "any" for a GenericSeries.
+// "float" and "datetime" are accepted as aliases for "float64" and "time".
+type Schema struct {
+	Fields []Field `json:"fields"`
+}
+
+// schemaTypes maps the accepted type names to the type of their Series, nil for a GenericSeries
+var schemaTypes = map[string]reflect.Type{
+	"int":      intType,
+	"float64":  float64Type,
+	"float":    float64Type,
+	"string":   stringType,
+	"bool":     boolType,
+	"time":     timeType,
+	"datetime": timeType,
+	"any":      nil,
+}
+
+// NewSchema creates a Schema from the fields, in order.
+//
+// Returns an error if a field has an unknown type or a name is used more than once.
+func NewSchema(fields ...Field) (*Schema, error) {
+	schema := &Schema{Fields: make([]Field, len(fields))}
+	for i, field := range fields {
+		typ, ok := schemaTypes[field.Type]
+		if !ok {
+			return nil, fmt.Errorf("Unknown type \"%s\" for field \"%s\"", field.Type, field.Name)
+		}
+		if slices.ContainsFunc(fields[:i], func(f Field) bool { return f.Name == field.Name }) {
+			return nil, fmt.Errorf("Duplicate field \"%s\"", field.Name)
+		}
+		field.Type = schemaTypeName(typ)
+		schema.Fields[i] = field
+	}
+	return schema, nil
+}
+
+// SchemaFromJSON creates a Schema from its JSON representation, as written by ToJSON
+func SchemaFromJSON(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("Error reading schema: %w", err)
+	}
+	return NewSchema(schema.Fields...)
+}
+
+// ToJSON returns the JSON representation of the Schema
+func (s *Schema) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Names returns the names of the fields, in order
+func (s *Schema) Names() []string {
+	names := make([]string, len(s.Fields))
+	for i, field := range s.Fields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+// Field returns the field with the given name, and whether it exists
+func (s *Schema) Field(name string) (Field, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Equal returns true if both schemas have the same fields in the same order.
+// Two nil schemas are equal.
+func (s *Schema) Equal(other *Schema) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return slices.Equal(s.Fields, other.Fields)
+}
+
+// Diff describes how other differs from s, one difference per entry.
+// Returns nil if the schemas are equal. A nil Schema has no fields.
+func (s *Schema) Diff(other *Schema) []string {
+	var diff []string
+	fields, otherFields := s.fields(), other.fields()
+	for i, field := range fields {
+		otherIndex := slices.IndexFunc(otherFields, func(f Field) bool { return f.Name == field.Name })
+		if otherIndex == -1 {
+			diff = append(diff, fmt.Sprintf("Missing field \"%s\"", field.Name))
+			continue
+		}
+
+		otherField := otherFields[otherIndex]
+		if otherIndex != i {
+			diff = append(diff, fmt.Sprintf("Field \"%s\" is at position %d, expected %d", field.Name, otherIndex, i))
+		}
+		if otherField.Type != field.Type {
+			diff = append(diff, fmt.Sprintf("Field \"%s\" has type %s, expected %s", field.Name, otherField.Type, field.Type))
+		}
+		if otherField.Nullable != field.Nullable {
+			diff = append(diff, fmt.Sprintf("Field \"%s\" has nullable %t, expected %t", field.Name, otherField.Nullable, field.Nullable))
+		}
+	}
+
+	for _, field := range otherFields {
+		if !slices.ContainsFunc(fields, func(f Field) bool { return f.Name == field.Name }) {
+			diff = append(diff, fmt.Sprintf("Unexpected field \"%s\"", field.Name))
+		}
+	}
+	return diff
+}
+
+// fields returns the fields of the Schema, or none for a nil Schema
+func (s *Schema) fields() []Field {
+	if s == nil {
+		return nil
+	}
+	return s.Fields
+}
+
+// Schema returns the Schema of the DataFrame.
+//
+// A column is nullable if it contains a null value.
+func (df *DataFrame) Schema() *Schema {
+	schema := &Schema{Fields: make([]Field, len(df.series))}
+	for i, s := range df.series {
+		schema.Fields[i] = Field{Name: s.Name(), Type: seriesTypeName(s), Nullable: hasNulls(s)}
+	}
+	return schema
+}
+
+// CastTo converts the DataFrame to match the Schema.
+//
+// The result has the columns of the Schema, in its order, converted to their declared types.
+// Columns that are not in the Schema are dropped.
+// Sets an error if the Schema is nil, or if a column is missing, can't be converted, or has nulls but is not nullable.
+func (df *DataFrame) CastTo(schema *Schema) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+	if schema == nil {
+		return df.withError(errors.New("Cannot cast to a nil schema"))
+	}
+
+	if missing := df.findColumnsThatDontExist(schema.Names()); len(missing) > 0 {
+		return df.withError(columnNotFound(missing...))
+	}
+
+	columns := make([]series.SeriesInterface, len(schema.Fields))
+	for i, field := range schema.Fields {
+		s := df.GetSeries(field.Name)
+		if seriesTypeName(s) != field.Type {
+			var err error
+			if field.Type == "any" {
+				s = s.ToGenericSeries()
+			} else if s, err = s.AsType(field.Type); err != nil {
+				return df.withError(err)
+			}
+		}
+
+		if !field.Nullable && hasNulls(s) {
+			return df.withError(fmt.Errorf("%w: column \"%s\" has nulls but is not nullable", ErrTypeConversion, field.Name))
+		}
+		columns[i] = s
+	}
+
+	result := df.target()
+	result.series = columns
+	return result
+}
+
+// parseColumn creates a Series of the field type from text values, in chunks of
+// series.DefaultChunkSize rows linked into one Series.
+// Empty values are nulls, except in string and any fields where they are empty strings.
+// With coerce, values that can't be converted are nulls too if the field is nullable.
+func parseColumn(field Field, values []string, coerce bool) (series.SeriesInterface, error) {
+	chunks := []series.SeriesInterface{}
+	for start := 0; start == 0 || start < len(values); start += series.DefaultChunkSize {
+		chunk, err := parseChunk(field, values[start:min(start+series.DefaultChunkSize, len(values))], start, coerce)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return series.Link(field.Name, chunks...), nil
+}
+
+// parseChunk parses the values of a chunk starting at firstRow
+func parseChunk(field Field, values []string, firstRow int, coerce bool) (series.SeriesInterface, error) {
+	parsed := make([]any, len(values))
+	for i, value := range values {
+		if value == "" && field.Type != "string" && field.Type != "any" {
+			if !field.Nullable {
+				return nil, fmt.Errorf("%w: column \"%s\" row %d is empty but is not nullable", ErrTypeConversion, field.Name, firstRow+i)
+			}
+			continue
+		}
+
+		var err error
+		switch field.Type {
+		case "any", "string":
+			parsed[i] = value
+		case "bool":
+			var b []bool
+			b, err = convertToBoolSlice([]string{value})
+			if err == nil {
+				parsed[i] = b[0]
+			}
+		default:
+			parsed[i], err = convert.ConvertValue(value, field.Type)
+		}
+		if err != nil && coerce && field.Nullable {
+			parsed[i] = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: column \"%s\" row %d: %v", ErrTypeConversion, field.Name, firstRow+i, err)
+		}
+	}
+	return newSeriesOfType(field.Name, schemaTypes[field.Type], parsed), nil
+}
+
+// seriesTypeName returns the Schema type name of a Series
+func seriesTypeName(s series.SeriesInterface) string {
+	if _, ok := s.(*series.GenericSeries); ok {
+		return "any"
+	}
+	return schemaTypeName(s.Type())
+}
+
+// schemaTypeName returns the Schema type name for the type of a Series
+func schemaTypeName(typ reflect.Type) string {
+	switch typ {
+	case intType:
+		return "int"
+	case float64Type:
+		return "float64"
+	case stringType:
+		return "string"
+	case boolType:
+		return "bool"
+	case timeType:
+		return "time"
+	}
+	return "any"
+}
+
+// hasNulls returns true if the Series has a null value
+func hasNulls(s series.SeriesInterface) bool {
+	for i := 0; i < s.Len(); i++ {
+		if s.IsNull(i) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package series
 
 import (
 	"fmt"
+	"iter"
 	"reflect"
 	"slices"
 )
@@ -9,6 +10,7 @@ import (
 type BoolSeries struct {
 	name   string
 	values []bool
+	nulls  []bool
 }
 
 // Implementation for BoolSeries
@@ -16,19 +18,45 @@ func NewBoolSeries(name string, values []bool) *BoolSeries {
 	return &BoolSeries{name: name, values: values}
 }
 
+// NewBoolSeriesWithNulls creates a BoolSeries where nulls[i] marks values[i] as missing
+func NewBoolSeriesWithNulls(name string, values []bool, nulls []bool) *BoolSeries {
+	return &BoolSeries{name: name, values: values, nulls: nulls}
+}
+
 func (s *BoolSeries) Name() string { return s.name }
 func (s *BoolSeries) Rename(newName string) SeriesInterface {
-	s.name = newName
-	return s
+	return NewBoolSeriesWithNulls(newName, s.values, s.nulls)
+}
+func (s *BoolSeries) Type() reflect.Type    { return reflect.TypeOf(true) }
+func (s *BoolSeries) Len() int              { return len(s.values) }
+func (s *BoolSeries) IsNull(index int) bool { return s.nulls != nil && s.nulls[index] }
+
+func (s *BoolSeries) Get(index int) any {
+	if s.IsNull(index) {
+		return nil
+	}
+	return s.values[index]
+}
+
+// Value returns the value at the specified index, or the zero value if it is null
+func (s *BoolSeries) Value(index int) bool { return s.values[index] }
+
+// All returns an iterator over the index and value of each element.
+// Null elements yield the zero value.
+func (s *BoolSeries) All() iter.Seq2[int, bool] {
+	return func(yield func(int, bool) bool) {
+		for i, v := range s.values {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
 }
-func (s *BoolSeries) Type() reflect.Type { return reflect.TypeOf(true) }
-func (s *BoolSeries) Get(index int) any  { return s.values[index] }
-func (s *BoolSeries) Len() int           { return len(s.values) }
 
 func (s *BoolSeries) Values() []any {
 	result := make([]any, len(s.values))
-	for i, v := range s.values {
-		result[i] = v
+	for i := range s.values {
+		result[i] = s.Get(i)
 	}
 	return result
 }
@@ -37,39 +65,29 @@ func (s *BoolSeries) Copy(deep bool) SeriesInterface {
 	if deep {
 		newValues := make([]bool, len(s.values))
 		copy(newValues, s.values)
-		return NewBoolSeries(s.name, newValues)
+		return NewBoolSeriesWithNulls(s.name, newValues, slices.Clone(s.nulls))
 	}
-	return NewBoolSeries(s.name, s.values)
+	return NewBoolSeriesWithNulls(s.name, s.values, s.nulls)
 }
 
 func (s *BoolSeries) DropRow(index int) SeriesInterface {
-	if index < 0 || index >= len(s.values) {
-		return s
-	}
-	s.values = slices.Delete(s.values, index, index+1)
-	return s
+	return s.DropRows(index)
 }
 
 func (s *BoolSeries) DropRows(indexes ...int) SeriesInterface {
-	slices.Sort(indexes)
-	slices.Reverse(indexes)
-	for _, i := range indexes {
-		if i >= 0 && i < len(s.values) {
-			s.DropRow(i)
-		}
-	}
-	return s
+	values, nulls := dropRows(s.values, s.nulls, indexes)
+	return NewBoolSeriesWithNulls(s.name, values, nulls)
 }
 
 func (s *BoolSeries) ToGenericSeries() *GenericSeries {
 	values := make([]any, len(s.values))
-	for i, v := range s.values {
-		values[i] = v
+	for i := range s.values {
+		values[i] = s.Get(i)
 	}
 	return NewGenericSeries(s.name, values)
 }
 
-func (s *BoolSeries) AsType(valueType string) SeriesInterface {
+func (s *BoolSeries) AsType(valueType string) (SeriesInterface, error) {
 	switch valueType {
 	case "int":
 		values := make([]int, len(s.values))
@@ -80,7 +98,7 @@ func (s *BoolSeries) AsType(valueType string) SeriesInterface {
 				values[i] = 0
 			}
 		}
-		return NewIntSeries(s.name, values)
+		return NewIntSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "float", "float64":
 		values := make([]float64, len(s.values))
 		for i, v := range s.values {
@@ -90,15 +108,15 @@ func (s *BoolSeries) AsType(valueType string) SeriesInterface {
 				values[i] = 0.0
 			}
 		}
-		return NewFloat64Series(s.name, values)
+		return NewFloat64SeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "string":
 		values := make([]string, len(s.values))
 		for i, v := range s.values {
 			values[i] = fmt.Sprint(v)
 		}
-		return NewStringSeries(s.name, values)
+		return NewStringSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "bool":
-		return s
+		return s, nil
 	default:
 		// Fall back to generic series for unsupported types
 		return s.ToGenericSeries().AsType(valueType)
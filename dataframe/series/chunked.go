@@ -0,0 +1,293 @@
+package series
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// DefaultChunkSize is the number of rows in the chunks created by readers and by appending rows
+const DefaultChunkSize = 64 * 1024
+
+// ChunkedSeries is a column stored as a list of chunks, each one a Series.
+//
+// Linking Series as chunks doesn't copy their values, so appending to or
+// concatenating columns only copies the chunk list. Chunks are never modified,
+// so they can be shared with other Series.
+type ChunkedSeries struct {
+	name    string
+	chunks  []SeriesInterface
+	offsets []int // offsets[i] is the first row of chunks[i], the last entry is the length
+	typ     reflect.Type
+}
+
+// NewChunkedSeries creates a ChunkedSeries from the chunks, in order.
+//
+// Chunks that are themselves chunked are flattened and empty chunks are skipped.
+// The type of the Series is the type of the chunks, or nil if they don't all
+// have the same type.
+func NewChunkedSeries(name string, chunks ...SeriesInterface) *ChunkedSeries {
+	s := &ChunkedSeries{name: name, offsets: []int{0}}
+	for _, chunk := range chunks {
+		if chunked, ok := chunk.(*ChunkedSeries); ok {
+			s.link(chunked.chunks...)
+		} else {
+			s.link(chunk)
+		}
+	}
+
+	for i, chunk := range s.chunks {
+		if i == 0 {
+			s.typ = chunk.Type()
+		} else if chunk.Type() != s.typ || reflect.TypeOf(chunk) != reflect.TypeOf(s.chunks[0]) {
+			s.typ = nil
+			break
+		}
+	}
+	return s
+}
+
+// Link joins the Series into one, linking them as chunks without copying their values.
+// Empty Series are skipped, and a single Series is returned as it is.
+func Link(name string, parts ...SeriesInterface) SeriesInterface {
+	if nonEmpty := slices.DeleteFunc(slices.Clone(parts), func(part SeriesInterface) bool { return part.Len() == 0 }); len(nonEmpty) > 0 {
+		parts = nonEmpty
+	} else if len(parts) > 0 {
+		parts = parts[:1]
+	}
+	if len(parts) == 1 {
+		if parts[0].Name() == name {
+			return parts[0]
+		}
+		return parts[0].Rename(name)
+	}
+	return NewChunkedSeries(name, parts...)
+}
+
+func (s *ChunkedSeries) link(chunks ...SeriesInterface) {
+	for _, chunk := range chunks {
+		if chunk.Len() == 0 {
+			continue
+		}
+		s.chunks = append(s.chunks, chunk)
+		s.offsets = append(s.offsets, s.Len()+chunk.Len())
+	}
+}
+
+// Locate returns the chunk holding a row and the position of the row in it
+func (s *ChunkedSeries) Locate(index int) (SeriesInterface, int) {
+	i, found := slices.BinarySearch(s.offsets, index)
+	if !found {
+		i--
+	}
+	return s.chunks[i], index - s.offsets[i]
+}
+
+func (s *ChunkedSeries) Name() string { return s.name }
+func (s *ChunkedSeries) Rename(newName string) SeriesInterface {
+	return &ChunkedSeries{name: newName, chunks: s.chunks, offsets: s.offsets, typ: s.typ}
+}
+func (s *ChunkedSeries) Type() reflect.Type { return s.typ }
+func (s *ChunkedSeries) Len() int           { return s.offsets[len(s.offsets)-1] }
+
+func (s *ChunkedSeries) Get(index int) any {
+	chunk, i := s.Locate(index)
+	return chunk.Get(i)
+}
+
+func (s *ChunkedSeries) IsNull(index int) bool {
+	chunk, i := s.Locate(index)
+	return chunk.IsNull(i)
+}
+
+// Chunks returns the chunks of the Series
+func (s *ChunkedSeries) Chunks() []SeriesInterface {
+	return slices.Clone(s.chunks)
+}
+
+// All returns an iterator over the index and value of each element, across chunks.
+// Null elements yield nil.
+func (s *ChunkedSeries) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		for i, chunk := range s.chunks {
+			for j := range chunk.Len() {
+				if !yield(s.offsets[i]+j, chunk.Get(j)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *ChunkedSeries) Values() []any {
+	result := make([]any, 0, s.Len())
+	for _, v := range s.All() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// Slice returns a view of the rows from start to end, without copying values
+func (s *ChunkedSeries) Slice(start, end int) SeriesInterface {
+	chunks := []SeriesInterface{}
+	for i, chunk := range s.chunks {
+		from, to := max(start-s.offsets[i], 0), min(end-s.offsets[i], chunk.Len())
+		if from < to {
+			chunks = append(chunks, Slice(chunk, from, to))
+		}
+	}
+	return &ChunkedSeries{name: s.name, chunks: chunks, offsets: sliceOffsets(chunks), typ: s.typ}
+}
+
+func sliceOffsets(chunks []SeriesInterface) []int {
+	offsets := []int{0}
+	for _, chunk := range chunks {
+		offsets = append(offsets, offsets[len(offsets)-1]+chunk.Len())
+	}
+	return offsets
+}
+
+// Compact returns the values of all chunks in a single Series
+func (s *ChunkedSeries) Compact() SeriesInterface {
+	if len(s.chunks) == 0 {
+		return NewGenericSeries(s.name, []any{})
+	}
+	if s.typ == nil {
+		return NewGenericSeries(s.name, s.Values())
+	}
+
+	switch s.chunks[0].(type) {
+	case *IntSeries:
+		values, nulls := compactChunks(s.chunks, func(c SeriesInterface) ([]int, []bool) {
+			return c.(*IntSeries).values, c.(*IntSeries).nulls
+		})
+		return NewIntSeriesWithNulls(s.name, values, nulls)
+	case *Float64Series:
+		values, nulls := compactChunks(s.chunks, func(c SeriesInterface) ([]float64, []bool) {
+			return c.(*Float64Series).values, c.(*Float64Series).nulls
+		})
+		return NewFloat64SeriesWithNulls(s.name, values, nulls)
+	case *StringSeries:
+		values, nulls := compactChunks(s.chunks, func(c SeriesInterface) ([]string, []bool) {
+			return c.(*StringSeries).values, c.(*StringSeries).nulls
+		})
+		return NewStringSeriesWithNulls(s.name, values, nulls)
+	case *BoolSeries:
+		values, nulls := compactChunks(s.chunks, func(c SeriesInterface) ([]bool, []bool) {
+			return c.(*BoolSeries).values, c.(*BoolSeries).nulls
+		})
+		return NewBoolSeriesWithNulls(s.name, values, nulls)
+	case *TimeSeries:
+		values, nulls := compactChunks(s.chunks, func(c SeriesInterface) ([]time.Time, []bool) {
+			return c.(*TimeSeries).values, c.(*TimeSeries).nulls
+		})
+		return NewTimeSeriesWithNulls(s.name, values, nulls)
+	}
+	return NewGenericSeries(s.name, s.Values())
+}
+
+// compactChunks copies the values and nulls of the chunks into single slices.
+// The nulls are nil if no chunk has nulls.
+func compactChunks[T any](chunks []SeriesInterface, parts func(SeriesInterface) ([]T, []bool)) ([]T, []bool) {
+	var values []T
+	var nulls []bool
+	for _, chunk := range chunks {
+		chunkValues, chunkNulls := parts(chunk)
+		if chunkNulls != nil && nulls == nil {
+			nulls = make([]bool, len(values), cap(values))
+		}
+		if nulls != nil {
+			if chunkNulls == nil {
+				chunkNulls = make([]bool, len(chunkValues))
+			}
+			nulls = append(nulls, chunkNulls...)
+		}
+		values = append(values, chunkValues...)
+	}
+	return values, nulls
+}
+
+func (s *ChunkedSeries) Copy(deep bool) SeriesInterface {
+	if deep {
+		return s.Compact()
+	}
+	return s.Rename(s.name)
+}
+
+func (s *ChunkedSeries) DropRow(index int) SeriesInterface {
+	return s.DropRows(index)
+}
+
+// DropRows returns the Series without the rows. Only the chunks that hold one of the rows are copied.
+func (s *ChunkedSeries) DropRows(indexes ...int) SeriesInterface {
+	sorted := slices.Clone(indexes)
+	slices.Sort(sorted)
+
+	chunks := make([]SeriesInterface, len(s.chunks))
+	for i, chunk := range s.chunks {
+		from, _ := slices.BinarySearch(sorted, s.offsets[i])
+		to, _ := slices.BinarySearch(sorted, s.offsets[i+1])
+		chunks[i] = chunk
+		if from < to {
+			local := make([]int, to-from)
+			for j, index := range sorted[from:to] {
+				local[j] = index - s.offsets[i]
+			}
+			chunks[i] = chunk.DropRows(local...)
+		}
+	}
+	return NewChunkedSeries(s.name, chunks...)
+}
+
+func (s *ChunkedSeries) ToGenericSeries() *GenericSeries {
+	return NewGenericSeries(s.name, s.Values())
+}
+
+// AsType converts each chunk, keeping the chunks of the Series
+func (s *ChunkedSeries) AsType(valueType string) (SeriesInterface, error) {
+	chunks := make([]SeriesInterface, len(s.chunks))
+	for i, chunk := range s.chunks {
+		converted, err := chunk.AsType(valueType)
+		if err != nil {
+			return s, err
+		}
+		chunks[i] = converted
+	}
+	return NewChunkedSeries(s.name, chunks...), nil
+}
+
+// Slice returns a view of the rows of a Series from start to end.
+//
+// Typed Series share their values with the view, so it is created without copying them.
+func Slice(s SeriesInterface, start, end int) SeriesInterface {
+	switch typed := s.(type) {
+	case *ChunkedSeries:
+		return typed.Slice(start, end)
+	case *IntSeries:
+		return NewIntSeriesWithNulls(typed.name, typed.values[start:end:end], sliceNulls(typed.nulls, start, end))
+	case *Float64Series:
+		return NewFloat64SeriesWithNulls(typed.name, typed.values[start:end:end], sliceNulls(typed.nulls, start, end))
+	case *StringSeries:
+		return NewStringSeriesWithNulls(typed.name, typed.values[start:end:end], sliceNulls(typed.nulls, start, end))
+	case *BoolSeries:
+		return NewBoolSeriesWithNulls(typed.name, typed.values[start:end:end], sliceNulls(typed.nulls, start, end))
+	case *TimeSeries:
+		return NewTimeSeriesWithNulls(typed.name, typed.values[start:end:end], sliceNulls(typed.nulls, start, end))
+	case *GenericSeries:
+		return &GenericSeries{name: typed.name, values: typed.values[start:end:end], typ: typed.typ}
+	}
+
+	values := make([]any, end-start)
+	for i := range values {
+		values[i] = s.Get(start + i)
+	}
+	return NewSeries(s.Name(), values)
+}
+
+func sliceNulls(nulls []bool, start, end int) []bool {
+	if nulls == nil {
+		return nil
+	}
+	return nulls[start:end:end]
+}
@@ -2,6 +2,7 @@ package series
 
 import (
 	"fmt"
+	"iter"
 	"reflect"
 	"slices"
 )
@@ -9,6 +10,7 @@ import (
 type Float64Series struct {
 	name   string
 	values []float64
+	nulls  []bool
 }
 
 // Implementation for Float64Series
@@ -16,19 +18,45 @@ func NewFloat64Series(name string, values []float64) *Float64Series {
 	return &Float64Series{name: name, values: values}
 }
 
+// NewFloat64SeriesWithNulls creates a Float64Series where nulls[i] marks values[i] as missing
+func NewFloat64SeriesWithNulls(name string, values []float64, nulls []bool) *Float64Series {
+	return &Float64Series{name: name, values: values, nulls: nulls}
+}
+
 func (s *Float64Series) Name() string { return s.name }
 func (s *Float64Series) Rename(newName string) SeriesInterface {
-	s.name = newName
-	return s
+	return NewFloat64SeriesWithNulls(newName, s.values, s.nulls)
+}
+func (s *Float64Series) Type() reflect.Type    { return reflect.TypeOf(0.0) }
+func (s *Float64Series) Len() int              { return len(s.values) }
+func (s *Float64Series) IsNull(index int) bool { return s.nulls != nil && s.nulls[index] }
+
+func (s *Float64Series) Get(index int) any {
+	if s.IsNull(index) {
+		return nil
+	}
+	return s.values[index]
+}
+
+// Value returns the value at the specified index, or the zero value if it is null
+func (s *Float64Series) Value(index int) float64 { return s.values[index] }
+
+// All returns an iterator over the index and value of each element.
+// Null elements yield the zero value.
+func (s *Float64Series) All() iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		for i, v := range s.values {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
 }
-func (s *Float64Series) Type() reflect.Type { return reflect.TypeOf(0.0) }
-func (s *Float64Series) Get(index int) any  { return s.values[index] }
-func (s *Float64Series) Len() int           { return len(s.values) }
 
 func (s *Float64Series) Values() []any {
 	result := make([]any, len(s.values))
-	for i, v := range s.values {
-		result[i] = v
+	for i := range s.values {
+		result[i] = s.Get(i)
 	}
 	return result
 }
@@ -37,60 +65,50 @@ func (s *Float64Series) Copy(deep bool) SeriesInterface {
 	if deep {
 		newValues := make([]float64, len(s.values))
 		copy(newValues, s.values)
-		return NewFloat64Series(s.name, newValues)
+		return NewFloat64SeriesWithNulls(s.name, newValues, slices.Clone(s.nulls))
 	}
-	return NewFloat64Series(s.name, s.values)
+	return NewFloat64SeriesWithNulls(s.name, s.values, s.nulls)
 }
 
 func (s *Float64Series) DropRow(index int) SeriesInterface {
-	if index < 0 || index >= len(s.values) {
-		return s
-	}
-	s.values = slices.Delete(s.values, index, index+1)
-	return s
+	return s.DropRows(index)
 }
 
 func (s *Float64Series) DropRows(indexes ...int) SeriesInterface {
-	slices.Sort(indexes)
-	slices.Reverse(indexes)
-	for _, i := range indexes {
-		if i >= 0 && i < len(s.values) {
-			s.DropRow(i)
-		}
-	}
-	return s
+	values, nulls := dropRows(s.values, s.nulls, indexes)
+	return NewFloat64SeriesWithNulls(s.name, values, nulls)
 }
 
 func (s *Float64Series) ToGenericSeries() *GenericSeries {
 	values := make([]any, len(s.values))
-	for i, v := range s.values {
-		values[i] = v
+	for i := range s.values {
+		values[i] = s.Get(i)
 	}
 	return NewGenericSeries(s.name, values)
 }
 
-func (s *Float64Series) AsType(valueType string) SeriesInterface {
+func (s *Float64Series) AsType(valueType string) (SeriesInterface, error) {
 	switch valueType {
 	case "int":
 		values := make([]int, len(s.values))
 		for i, v := range s.values {
 			values[i] = int(v)
 		}
-		return NewIntSeries(s.name, values)
+		return NewIntSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "float", "float64":
-		return s
+		return s, nil
 	case "string":
 		values := make([]string, len(s.values))
 		for i, v := range s.values {
 			values[i] = fmt.Sprint(v)
 		}
-		return NewStringSeries(s.name, values)
+		return NewStringSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "bool":
 		values := make([]bool, len(s.values))
 		for i, v := range s.values {
 			values[i] = v != 0
 		}
-		return NewBoolSeries(s.name, values)
+		return NewBoolSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	default:
 		// Fall back to generic series for unsupported types
 		return s.ToGenericSeries().AsType(valueType)
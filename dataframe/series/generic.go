@@ -1,19 +1,24 @@
 package series
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
 	"strconv"
 	convert "teddy/dataframe/convert"
+	"time"
 )
 
 // SeriesInterface defines common operations for all Series types
+//
+// Series are not modified after they are created, operations return a new Series
+// that may share the underlying values. This lets DataFrames share Series safely.
 type SeriesInterface interface {
 	// Get the Series name
 	Name() string
 
-	// Rename the Series
+	// Return a copy of the Series with a new name
 	Rename(newName string) SeriesInterface
 
 	// Get the Go type of the Series values
@@ -22,13 +27,16 @@ type SeriesInterface interface {
 	// Get the value at the specified index as interface{}
 	Get(index int) any
 
+	// Check whether the value at the specified index is missing
+	IsNull(index int) bool
+
 	// Create a copy of the Series
 	Copy(deep bool) SeriesInterface
 
-	// Drop a row from the Series
+	// Return a copy of the Series without the row
 	DropRow(index int) SeriesInterface
 
-	// Drop rows from the Series
+	// Return a copy of the Series without the rows
 	DropRows(indexes ...int) SeriesInterface
 
 	// Get the length of the Series
@@ -38,12 +46,15 @@ type SeriesInterface interface {
 	ToGenericSeries() *GenericSeries
 
 	// Change the type of values
-	AsType(valueType string) SeriesInterface
+	AsType(valueType string) (SeriesInterface, error)
 
 	// Get all values as a slice of any
 	Values() []any
 }
 
+// ErrTypeConversion is returned when the values of a Series can't be converted to a type
+var ErrTypeConversion = errors.New("type conversion failed")
+
 // GenericSeries is equivalent to the original Series implementation
 type GenericSeries struct {
 	name   string
@@ -70,13 +81,13 @@ func NewGenericSeries(name string, values []any) *GenericSeries {
 
 func (s *GenericSeries) Name() string { return s.name }
 func (s *GenericSeries) Rename(newName string) SeriesInterface {
-	s.name = newName
-	return s
+	return &GenericSeries{name: newName, values: s.values, typ: s.typ}
 }
-func (s *GenericSeries) Type() reflect.Type { return s.typ }
-func (s *GenericSeries) Get(index int) any  { return s.values[index] }
-func (s *GenericSeries) Len() int           { return len(s.values) }
-func (s *GenericSeries) Values() []any      { return s.values }
+func (s *GenericSeries) Type() reflect.Type    { return s.typ }
+func (s *GenericSeries) Get(index int) any     { return s.values[index] }
+func (s *GenericSeries) Len() int              { return len(s.values) }
+func (s *GenericSeries) Values() []any         { return slices.Clone(s.values) }
+func (s *GenericSeries) IsNull(index int) bool { return s.values[index] == nil }
 
 func (s *GenericSeries) Copy(deep bool) SeriesInterface {
 	if deep {
@@ -88,93 +99,111 @@ func (s *GenericSeries) Copy(deep bool) SeriesInterface {
 }
 
 func (s *GenericSeries) DropRow(index int) SeriesInterface {
-	if index < 0 || index >= len(s.values) {
-		return s
-	}
-	s.values = slices.Delete(s.values, index, index+1)
-	return s
+	return s.DropRows(index)
 }
 
 func (s *GenericSeries) DropRows(indexes ...int) SeriesInterface {
-	slices.Sort(indexes)
-	slices.Reverse(indexes)
-	for _, i := range indexes {
-		if i >= 0 && i < len(s.values) {
-			s.DropRow(i)
-		}
-	}
-	return s
+	values, _ := dropRows(s.values, nil, indexes)
+	return &GenericSeries{name: s.name, values: values, typ: s.typ}
 }
 
 func (s *GenericSeries) ToGenericSeries() *GenericSeries {
 	return s
 }
 
-func (s *GenericSeries) AsType(valueType string) SeriesInterface {
+func (s *GenericSeries) AsType(valueType string) (SeriesInterface, error) {
 	// Try to convert to a specialized series if possible
 	switch valueType {
 	case "int":
-		values, ok := ToIntSlice(s.values)
+		filled, nulls := fillNulls(s.values, 0)
+		values, ok := ToIntSlice(filled)
 		if ok {
-			return NewIntSeries(s.name, values)
+			return NewIntSeriesWithNulls(s.name, values, nulls), nil
 		}
 	case "float", "float64":
-		values, ok := ToFloat64Slice(s.values)
+		filled, nulls := fillNulls(s.values, 0.0)
+		values, ok := ToFloat64Slice(filled)
 		if ok {
-			return NewFloat64Series(s.name, values)
+			return NewFloat64SeriesWithNulls(s.name, values, nulls), nil
 		}
 	case "string":
-		values := ToStringSlice(s.values)
-		return NewStringSeries(s.name, values)
+		filled, nulls := fillNulls(s.values, "")
+		values := ToStringSlice(filled)
+		return NewStringSeriesWithNulls(s.name, values, nulls), nil
 	case "bool":
-		values, ok := ToBoolSlice(s.values)
+		filled, nulls := fillNulls(s.values, false)
+		values, ok := ToBoolSlice(filled)
 		if ok {
-			return NewBoolSeries(s.name, values)
+			return NewBoolSeriesWithNulls(s.name, values, nulls), nil
+		}
+	case "time", "datetime":
+		values, nulls, ok := ToTimeSlice(s.values)
+		if ok {
+			return NewTimeSeriesWithNulls(s.name, values, nulls), nil
 		}
 	}
 
 	// Fall back to converting each value individually
-	for i := range s.values {
-		value, err := convert.ConvertValue(s.values[i], valueType)
+	newValues := make([]any, len(s.values))
+	for i, value := range s.values {
+		if value == nil {
+			continue
+		}
+		newValue, err := convert.ConvertValue(value, valueType)
 		if err != nil {
-			fmt.Printf("Error converting value to type %s: %v\n", valueType, err)
-			return s
+			return s, fmt.Errorf("%w: column \"%s\" to %s: %v", ErrTypeConversion, s.name, valueType, err)
 		}
-		s.values[i] = value
+		newValues[i] = newValue
 	}
 
-	if len(s.values) > 0 {
-		s.typ = reflect.TypeOf(s.values[0])
-	}
-
-	return s
+	return NewGenericSeries(s.name, newValues), nil
 }
 
 // Factory function to create the appropriate Series type based on input data
+//
+// nil values are treated as missing and recorded in the null mask of the typed Series.
 func NewSeries(name string, values []any) SeriesInterface {
 	if len(values) == 0 {
 		return NewGenericSeries(name, values)
 	}
 
+	// Use the first non-nil value to determine the type
+	var first any
+	for _, value := range values {
+		if value != nil {
+			first = value
+			break
+		}
+	}
+
 	// Try to determine the type and convert to a specialized Series
-	switch values[0].(type) {
+	switch first.(type) {
 	case int:
-		intValues, ok := ToIntSlice(values)
+		filled, nulls := fillNulls(values, 0)
+		intValues, ok := ToIntSlice(filled)
 		if ok {
-			return NewIntSeries(name, intValues)
+			return NewIntSeriesWithNulls(name, intValues, nulls)
 		}
 	case float64:
-		floatValues, ok := ToFloat64Slice(values)
+		filled, nulls := fillNulls(values, 0.0)
+		floatValues, ok := ToFloat64Slice(filled)
 		if ok {
-			return NewFloat64Series(name, floatValues)
+			return NewFloat64SeriesWithNulls(name, floatValues, nulls)
 		}
 	case string:
-		stringValues := ToStringSlice(values)
-		return NewStringSeries(name, stringValues)
+		filled, nulls := fillNulls(values, "")
+		stringValues := ToStringSlice(filled)
+		return NewStringSeriesWithNulls(name, stringValues, nulls)
 	case bool:
-		boolValues, ok := ToBoolSlice(values)
+		filled, nulls := fillNulls(values, false)
+		boolValues, ok := ToBoolSlice(filled)
 		if ok {
-			return NewBoolSeries(name, boolValues)
+			return NewBoolSeriesWithNulls(name, boolValues, nulls)
+		}
+	case time.Time:
+		timeValues, nulls, ok := ToTimeSlice(values)
+		if ok {
+			return NewTimeSeriesWithNulls(name, timeValues, nulls)
 		}
 	}
 
@@ -182,7 +211,59 @@ func NewSeries(name string, values []any) SeriesInterface {
 	return NewGenericSeries(name, values)
 }
 
-func NewSeriesWithType(name string, values []any, valueType string) SeriesInterface {
+// dropRows returns copies of values and nulls without the rows at the given indexes.
+// Indexes that are out of range are ignored. The inputs are not modified.
+func dropRows[T any](values []T, nulls []bool, indexes []int) ([]T, []bool) {
+	drop := make([]bool, len(values))
+	dropCount := 0
+	for _, i := range indexes {
+		if i >= 0 && i < len(values) && !drop[i] {
+			drop[i] = true
+			dropCount++
+		}
+	}
+
+	newValues := make([]T, 0, len(values)-dropCount)
+	var newNulls []bool
+	if nulls != nil {
+		newNulls = make([]bool, 0, len(values)-dropCount)
+	}
+	for i, value := range values {
+		if drop[i] {
+			continue
+		}
+		newValues = append(newValues, value)
+		if nulls != nil {
+			newNulls = append(newNulls, nulls[i])
+		}
+	}
+	return newValues, newNulls
+}
+
+// fillNulls replaces nil values with zero and returns a mask of the replaced positions.
+// If there are no nil values, the input slice and a nil mask are returned.
+func fillNulls(values []any, zero any) ([]any, []bool) {
+	var filled []any
+	var nulls []bool
+	for i, value := range values {
+		if value != nil {
+			continue
+		}
+		if nulls == nil {
+			filled = make([]any, len(values))
+			copy(filled, values)
+			nulls = make([]bool, len(values))
+		}
+		filled[i] = zero
+		nulls[i] = true
+	}
+	if nulls == nil {
+		return values, nil
+	}
+	return filled, nulls
+}
+
+func NewSeriesWithType(name string, values []any, valueType string) (SeriesInterface, error) {
 	series := NewSeries(name, values)
 	return series.AsType(valueType)
 }
@@ -332,3 +413,26 @@ func ToBoolSlice(values []any) ([]bool, bool) {
 	}
 	return result, true
 }
+
+// ToTimeSlice converts values to times, parsing strings and treating ints as Unix timestamps.
+// nil values are returned as zero times marked in the null mask, which is nil if there are no nulls.
+func ToTimeSlice(values []any) ([]time.Time, []bool, bool) {
+	result := make([]time.Time, len(values))
+	var nulls []bool
+	for i, v := range values {
+		if v == nil {
+			if nulls == nil {
+				nulls = make([]bool, len(values))
+			}
+			nulls[i] = true
+			continue
+		}
+
+		value, err := convert.ConvertValue(v, "datetime")
+		if err != nil {
+			return nil, nil, false
+		}
+		result[i] = value.(time.Time)
+	}
+	return result, nulls, true
+}
@@ -2,6 +2,7 @@ package series
 
 import (
 	"fmt"
+	"iter"
 	"reflect"
 	"slices"
 )
@@ -9,6 +10,7 @@ import (
 type IntSeries struct {
 	name   string
 	values []int
+	nulls  []bool
 }
 
 // Implementation for IntSeries
@@ -16,19 +18,45 @@ func NewIntSeries(name string, values []int) *IntSeries {
 	return &IntSeries{name: name, values: values}
 }
 
+// NewIntSeriesWithNulls creates an IntSeries where nulls[i] marks values[i] as missing
+func NewIntSeriesWithNulls(name string, values []int, nulls []bool) *IntSeries {
+	return &IntSeries{name: name, values: values, nulls: nulls}
+}
+
 func (s *IntSeries) Name() string { return s.name }
 func (s *IntSeries) Rename(newName string) SeriesInterface {
-	s.name = newName
-	return s
+	return NewIntSeriesWithNulls(newName, s.values, s.nulls)
+}
+func (s *IntSeries) Type() reflect.Type    { return reflect.TypeOf(0) }
+func (s *IntSeries) Len() int              { return len(s.values) }
+func (s *IntSeries) IsNull(index int) bool { return s.nulls != nil && s.nulls[index] }
+
+func (s *IntSeries) Get(index int) any {
+	if s.IsNull(index) {
+		return nil
+	}
+	return s.values[index]
+}
+
+// Value returns the value at the specified index, or the zero value if it is null
+func (s *IntSeries) Value(index int) int { return s.values[index] }
+
+// All returns an iterator over the index and value of each element.
+// Null elements yield the zero value.
+func (s *IntSeries) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for i, v := range s.values {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
 }
-func (s *IntSeries) Type() reflect.Type { return reflect.TypeOf(0) }
-func (s *IntSeries) Get(index int) any  { return s.values[index] }
-func (s *IntSeries) Len() int           { return len(s.values) }
 
 func (s *IntSeries) Values() []any {
 	result := make([]any, len(s.values))
-	for i, v := range s.values {
-		result[i] = v
+	for i := range s.values {
+		result[i] = s.Get(i)
 	}
 	return result
 }
@@ -37,60 +65,50 @@ func (s *IntSeries) Copy(deep bool) SeriesInterface {
 	if deep {
 		newValues := make([]int, len(s.values))
 		copy(newValues, s.values)
-		return NewIntSeries(s.name, newValues)
+		return NewIntSeriesWithNulls(s.name, newValues, slices.Clone(s.nulls))
 	}
-	return NewIntSeries(s.name, s.values)
+	return NewIntSeriesWithNulls(s.name, s.values, s.nulls)
 }
 
 func (s *IntSeries) DropRow(index int) SeriesInterface {
-	if index < 0 || index >= len(s.values) {
-		return s
-	}
-	s.values = slices.Delete(s.values, index, index+1)
-	return s
+	return s.DropRows(index)
 }
 
 func (s *IntSeries) DropRows(indexes ...int) SeriesInterface {
-	slices.Sort(indexes)
-	slices.Reverse(indexes)
-	for _, i := range indexes {
-		if i >= 0 && i < len(s.values) {
-			s.DropRow(i)
-		}
-	}
-	return s
+	values, nulls := dropRows(s.values, s.nulls, indexes)
+	return NewIntSeriesWithNulls(s.name, values, nulls)
 }
 
 func (s *IntSeries) ToGenericSeries() *GenericSeries {
 	values := make([]any, len(s.values))
-	for i, v := range s.values {
-		values[i] = v
+	for i := range s.values {
+		values[i] = s.Get(i)
 	}
 	return NewGenericSeries(s.name, values)
 }
 
-func (s *IntSeries) AsType(valueType string) SeriesInterface {
+func (s *IntSeries) AsType(valueType string) (SeriesInterface, error) {
 	switch valueType {
 	case "int":
-		return s
+		return s, nil
 	case "float", "float64":
 		values := make([]float64, len(s.values))
 		for i, v := range s.values {
 			values[i] = float64(v)
 		}
-		return NewFloat64Series(s.name, values)
+		return NewFloat64SeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "string":
 		values := make([]string, len(s.values))
 		for i, v := range s.values {
 			values[i] = fmt.Sprint(v)
 		}
-		return NewStringSeries(s.name, values)
+		return NewStringSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "bool":
 		values := make([]bool, len(s.values))
 		for i, v := range s.values {
 			values[i] = v != 0
 		}
-		return NewBoolSeries(s.name, values)
+		return NewBoolSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	default:
 		// Fall back to generic series for unsupported types
 		return s.ToGenericSeries().AsType(valueType)
@@ -0,0 +1,68 @@
+package series
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// Sizes of the values in memory
+var (
+	interfaceSize = int64(unsafe.Sizeof(any(nil)))
+	stringSize    = int64(unsafe.Sizeof(""))
+	timeSize      = int64(unsafe.Sizeof(time.Time{}))
+)
+
+// MemoryUsage returns the number of bytes used by the values and null mask of a Series.
+//
+// Without deep, only the slices of the Series are counted, so strings count their
+// headers and GenericSeries values count their interfaces. With deep, the bytes of
+// the strings and of the values boxed in interfaces are counted too.
+// Chunks shared with other Series are counted in full.
+func MemoryUsage(s SeriesInterface, deep bool) int64 {
+	switch typed := s.(type) {
+	case *IntSeries:
+		return int64(len(typed.values))*8 + int64(len(typed.nulls))
+	case *Float64Series:
+		return int64(len(typed.values))*8 + int64(len(typed.nulls))
+	case *BoolSeries:
+		return int64(len(typed.values)) + int64(len(typed.nulls))
+	case *TimeSeries:
+		return int64(len(typed.values))*timeSize + int64(len(typed.nulls))
+	case *StringSeries:
+		bytes := int64(len(typed.values))*stringSize + int64(len(typed.nulls))
+		if deep {
+			for _, v := range typed.values {
+				bytes += int64(len(v))
+			}
+		}
+		return bytes
+	case *ChunkedSeries:
+		bytes := int64(len(typed.chunks))*interfaceSize + int64(len(typed.offsets))*8
+		for _, chunk := range typed.chunks {
+			bytes += MemoryUsage(chunk, deep)
+		}
+		return bytes
+	}
+
+	bytes := int64(s.Len()) * interfaceSize
+	if deep {
+		for i := range s.Len() {
+			bytes += boxedSize(s.Get(i))
+		}
+	}
+	return bytes
+}
+
+// boxedSize returns the number of bytes a value uses when it is stored in an interface,
+// besides the interface itself
+func boxedSize(value any) int64 {
+	switch v := value.(type) {
+	case nil, bool:
+		// Booleans are not allocated
+		return 0
+	case string:
+		return stringSize + int64(len(v))
+	}
+	return int64(reflect.TypeOf(value).Size())
+}
@@ -2,6 +2,7 @@ package series
 
 import (
 	"fmt"
+	"iter"
 	"reflect"
 	"slices"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 type StringSeries struct {
 	name   string
 	values []string
+	nulls  []bool
 }
 
 // Implementation for StringSeries
@@ -18,19 +20,45 @@ func NewStringSeries(name string, values []string) *StringSeries {
 	return &StringSeries{name: name, values: values}
 }
 
+// NewStringSeriesWithNulls creates a StringSeries where nulls[i] marks values[i] as missing
+func NewStringSeriesWithNulls(name string, values []string, nulls []bool) *StringSeries {
+	return &StringSeries{name: name, values: values, nulls: nulls}
+}
+
 func (s *StringSeries) Name() string { return s.name }
 func (s *StringSeries) Rename(newName string) SeriesInterface {
-	s.name = newName
-	return s
+	return NewStringSeriesWithNulls(newName, s.values, s.nulls)
+}
+func (s *StringSeries) Type() reflect.Type    { return reflect.TypeOf("") }
+func (s *StringSeries) Len() int              { return len(s.values) }
+func (s *StringSeries) IsNull(index int) bool { return s.nulls != nil && s.nulls[index] }
+
+func (s *StringSeries) Get(index int) any {
+	if s.IsNull(index) {
+		return nil
+	}
+	return s.values[index]
+}
+
+// Value returns the value at the specified index, or the zero value if it is null
+func (s *StringSeries) Value(index int) string { return s.values[index] }
+
+// All returns an iterator over the index and value of each element.
+// Null elements yield the zero value.
+func (s *StringSeries) All() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i, v := range s.values {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
 }
-func (s *StringSeries) Type() reflect.Type { return reflect.TypeOf("") }
-func (s *StringSeries) Get(index int) any  { return s.values[index] }
-func (s *StringSeries) Len() int           { return len(s.values) }
 
 func (s *StringSeries) Values() []any {
 	result := make([]any, len(s.values))
-	for i, v := range s.values {
-		result[i] = v
+	for i := range s.values {
+		result[i] = s.Get(i)
 	}
 	return result
 }
@@ -39,63 +67,50 @@ func (s *StringSeries) Copy(deep bool) SeriesInterface {
 	if deep {
 		newValues := make([]string, len(s.values))
 		copy(newValues, s.values)
-		return NewStringSeries(s.name, newValues)
+		return NewStringSeriesWithNulls(s.name, newValues, slices.Clone(s.nulls))
 	}
-	return NewStringSeries(s.name, s.values)
+	return NewStringSeriesWithNulls(s.name, s.values, s.nulls)
 }
 
 func (s *StringSeries) DropRow(index int) SeriesInterface {
-	if index < 0 || index >= len(s.values) {
-		return s
-	}
-	s.values = slices.Delete(s.values, index, index+1)
-	return s
+	return s.DropRows(index)
 }
 
 func (s *StringSeries) DropRows(indexes ...int) SeriesInterface {
-	slices.Sort(indexes)
-	slices.Reverse(indexes)
-	for _, i := range indexes {
-		if i >= 0 && i < len(s.values) {
-			s.DropRow(i)
-		}
-	}
-	return s
+	values, nulls := dropRows(s.values, s.nulls, indexes)
+	return NewStringSeriesWithNulls(s.name, values, nulls)
 }
 
 func (s *StringSeries) ToGenericSeries() *GenericSeries {
 	values := make([]any, len(s.values))
-	for i, v := range s.values {
-		values[i] = v
+	for i := range s.values {
+		values[i] = s.Get(i)
 	}
 	return NewGenericSeries(s.name, values)
 }
 
-func (s *StringSeries) AsType(valueType string) SeriesInterface {
+func (s *StringSeries) AsType(valueType string) (SeriesInterface, error) {
 	switch valueType {
 	case "int":
 		values, ok := StringSliceToIntSlice(s.values)
 		if !ok {
-			fmt.Println("Error converting string values to int")
-			return s
+			return s, fmt.Errorf("%w: column \"%s\" to int", ErrTypeConversion, s.name)
 		}
-		return NewIntSeries(s.name, values)
+		return NewIntSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "float", "float64":
 		values, ok := StringSliceToFloat64Slice(s.values)
 		if !ok {
-			fmt.Println("Error converting string values to float64")
-			return s
+			return s, fmt.Errorf("%w: column \"%s\" to float64", ErrTypeConversion, s.name)
 		}
-		return NewFloat64Series(s.name, values)
+		return NewFloat64SeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	case "string":
-		return s
+		return s, nil
 	case "bool":
 		values, ok := StringSliceToBoolSlice(s.values)
 		if !ok {
-			fmt.Println("Error converting string values to bool")
-			return s
+			return s, fmt.Errorf("%w: column \"%s\" to bool", ErrTypeConversion, s.name)
 		}
-		return NewBoolSeries(s.name, values)
+		return NewBoolSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
 	default:
 		// Fall back to generic series for unsupported types
 		return s.ToGenericSeries().AsType(valueType)
@@ -0,0 +1,111 @@
+package series
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"time"
+)
+
+type TimeSeries struct {
+	name   string
+	values []time.Time
+	nulls  []bool
+}
+
+// Implementation for TimeSeries
+func NewTimeSeries(name string, values []time.Time) *TimeSeries {
+	return &TimeSeries{name: name, values: values}
+}
+
+// NewTimeSeriesWithNulls creates a TimeSeries where nulls[i] marks values[i] as missing
+func NewTimeSeriesWithNulls(name string, values []time.Time, nulls []bool) *TimeSeries {
+	return &TimeSeries{name: name, values: values, nulls: nulls}
+}
+
+func (s *TimeSeries) Name() string { return s.name }
+func (s *TimeSeries) Rename(newName string) SeriesInterface {
+	return NewTimeSeriesWithNulls(newName, s.values, s.nulls)
+}
+func (s *TimeSeries) Type() reflect.Type    { return reflect.TypeOf(time.Time{}) }
+func (s *TimeSeries) Len() int              { return len(s.values) }
+func (s *TimeSeries) IsNull(index int) bool { return s.nulls != nil && s.nulls[index] }
+
+func (s *TimeSeries) Get(index int) any {
+	if s.IsNull(index) {
+		return nil
+	}
+	return s.values[index]
+}
+
+// Value returns the value at the specified index, or the zero value if it is null
+func (s *TimeSeries) Value(index int) time.Time { return s.values[index] }
+
+// All returns an iterator over the index and value of each element.
+// Null elements yield the zero value.
+func (s *TimeSeries) All() iter.Seq2[int, time.Time] {
+	return func(yield func(int, time.Time) bool) {
+		for i, v := range s.values {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s *TimeSeries) Values() []any {
+	result := make([]any, len(s.values))
+	for i := range s.values {
+		result[i] = s.Get(i)
+	}
+	return result
+}
+
+func (s *TimeSeries) Copy(deep bool) SeriesInterface {
+	if deep {
+		newValues := make([]time.Time, len(s.values))
+		copy(newValues, s.values)
+		return NewTimeSeriesWithNulls(s.name, newValues, slices.Clone(s.nulls))
+	}
+	return NewTimeSeriesWithNulls(s.name, s.values, s.nulls)
+}
+
+func (s *TimeSeries) DropRow(index int) SeriesInterface {
+	return s.DropRows(index)
+}
+
+func (s *TimeSeries) DropRows(indexes ...int) SeriesInterface {
+	values, nulls := dropRows(s.values, s.nulls, indexes)
+	return NewTimeSeriesWithNulls(s.name, values, nulls)
+}
+
+func (s *TimeSeries) ToGenericSeries() *GenericSeries {
+	values := make([]any, len(s.values))
+	for i := range s.values {
+		values[i] = s.Get(i)
+	}
+	return NewGenericSeries(s.name, values)
+}
+
+func (s *TimeSeries) AsType(valueType string) (SeriesInterface, error) {
+	switch valueType {
+	case "time", "datetime":
+		return s, nil
+	case "int":
+		// Unix timestamps in seconds
+		values := make([]int, len(s.values))
+		for i, v := range s.values {
+			values[i] = int(v.Unix())
+		}
+		return NewIntSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
+	case "string":
+		values := make([]string, len(s.values))
+		for i, v := range s.values {
+			values[i] = v.Format(time.RFC3339)
+		}
+		return NewStringSeriesWithNulls(s.name, values, slices.Clone(s.nulls)), nil
+	default:
+		// Fall back to generic series for unsupported types
+		return s.ToGenericSeries().AsType(valueType)
+	}
+}
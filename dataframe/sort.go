@@ -0,0 +1,108 @@
+package dataframe
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"teddy/dataframe/internal/compare"
+	"teddy/dataframe/series"
+)
+
+// Sort returns the DataFrame with the rows ordered by the columns.
+//
+// Rows are compared by the first column, then by the next column when equal, and so on.
+// The sort is stable and null values are placed last.
+//
+// Options:
+//   - descending: bool or []bool (default: false) Sort in descending order, for all columns or for each column.
+func (df *DataFrame) Sort(columns []string, options ...OptionsMap) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	descending, err := descendingOption(standardizeOptions(options...), len(columns))
+	if err != nil {
+		return df.withError(err)
+	}
+	if missing := df.findColumnsThatDontExist(columns); len(missing) > 0 {
+		return df.withError(columnNotFound(missing...))
+	}
+
+	rows := allRows(df.Height())
+	slices.SortStableFunc(rows, compareRows(df.seriesOf(columns), descending))
+
+	result := df.takeRows(rows)
+	if df.inPlace {
+		df.series, df.index = result.series, result.index
+		return df
+	}
+	return result
+}
+
+// descendingOption returns the descending option for each of n columns
+func descendingOption(options OptionsMap, n int) ([]bool, error) {
+	descending := make([]bool, n)
+	switch option := options.getOption("descending", false).(type) {
+	case bool:
+		for i := range descending {
+			descending[i] = option
+		}
+	case []bool:
+		if len(option) != n {
+			return nil, fmt.Errorf("%w: %d descending values for %d columns", ErrLengthMismatch, len(option), n)
+		}
+		copy(descending, option)
+	}
+	return descending, nil
+}
+
+// seriesOf returns the Series of the columns, which must exist
+func (df *DataFrame) seriesOf(columns []string) []series.SeriesInterface {
+	keys := make([]series.SeriesInterface, len(columns))
+	for i, column := range columns {
+		keys[i] = df.GetSeries(column)
+	}
+	return keys
+}
+
+// compareRows returns a function that compares two rows by the keys, then by the next key when equal.
+// Nulls are last in both directions.
+func compareRows(keys []series.SeriesInterface, descending []bool) func(a, b int) int {
+	return func(a, b int) int {
+		for i, s := range keys {
+			aNull, bNull := s.IsNull(a), s.IsNull(b)
+			if aNull || bNull {
+				if aNull && bNull {
+					continue
+				}
+				if aNull {
+					return 1
+				}
+				return -1
+			}
+
+			result := compareValues(s.Get(a), s.Get(b))
+			if descending[i] {
+				result = -result
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+}
+
+// compareValues compares two non-null values.
+// Numbers are compared numerically, times chronologically, false before true,
+// and other values by their string representation.
+func compareValues(a, b any) int {
+	if result, ok := compare.Values(a, b); ok {
+		return result
+	}
+	if numbers, ok := series.ToFloat64Slice([]any{a, b}); ok {
+		return cmp.Compare(numbers[0], numbers[1])
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,67 @@
+package sql
+
+import (
+	"strings"
+	"teddy/dataframe/internal/lexer"
+)
+
+// tokenKind is the kind of a token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenKeyword
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+// token is a word, literal or symbol of a query, with its position in the query
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var keywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "BY": true, "HAVING": true,
+	"ORDER": true, "ASC": true, "DESC": true, "LIMIT": true, "JOIN": true, "INNER": true,
+	"LEFT": true, "OUTER": true, "ON": true, "AS": true, "AND": true, "OR": true, "NOT": true,
+	"IS": true, "NULL": true, "IN": true, "LIKE": true, "TRUE": true, "FALSE": true,
+}
+
+// queryLexer splits queries into tokens. Strings are quoted with single quotes, and a quote
+// is escaped by doubling it. Names can be quoted with double quotes or backticks.
+var queryLexer = &lexer.Lexer{
+	Symbols:       []string{"<=", ">=", "<>", "!=", "(", ")", ",", ".", "*", "=", "<", ">", "+", "-", "/"},
+	NameQuotes:    "\"`",
+	StringQuotes:  "'",
+	DoubledQuotes: true,
+}
+
+// tokenKinds maps the kinds of the lexer to the kinds of query tokens
+var tokenKinds = map[lexer.Kind]tokenKind{
+	lexer.EOF:    tokenEOF,
+	lexer.Ident:  tokenIdent,
+	lexer.Number: tokenNumber,
+	lexer.String: tokenString,
+	lexer.Symbol: tokenSymbol,
+}
+
+// tokenize splits a query into tokens. Keywords are returned in upper case, unless they are quoted.
+func tokenize(query string) ([]token, error) {
+	lexed, err := queryLexer.Tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]token, len(lexed))
+	for i, t := range lexed {
+		tokens[i] = token{kind: tokenKinds[t.Kind], text: t.Text, pos: t.Pos}
+		if t.Kind == lexer.Ident && !t.Quoted && keywords[strings.ToUpper(t.Text)] {
+			tokens[i] = token{kind: tokenKeyword, text: strings.ToUpper(t.Text), pos: t.Pos}
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,604 @@
+package sql
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// node is an expression of a query
+type node interface {
+	String() string
+}
+
+// columnRef is a column, optionally qualified by its table
+type columnRef struct {
+	table string
+	name  string
+}
+
+// literal is a constant value
+type literal struct {
+	value any
+}
+
+// binaryExpr is an arithmetic, comparison, logical or LIKE operation
+type binaryExpr struct {
+	op    string
+	left  node
+	right node
+}
+
+// unaryExpr is NOT or a negation
+type unaryExpr struct {
+	op      string
+	operand node
+}
+
+// isNullExpr is IS NULL or IS NOT NULL
+type isNullExpr struct {
+	operand node
+	not     bool
+}
+
+// inExpr is IN or NOT IN with a list of values
+type inExpr struct {
+	operand node
+	values  []node
+	not     bool
+}
+
+// funcCall is an aggregate function. COUNT(*) has a nil argument.
+type funcCall struct {
+	name string
+	arg  node
+}
+
+func (c *columnRef) String() string {
+	if c.table != "" {
+		return c.table + "." + c.name
+	}
+	return c.name
+}
+
+func (l *literal) String() string {
+	switch v := l.value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return fmt.Sprint(l.value)
+}
+
+func (b *binaryExpr) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.left, b.op, b.right)
+}
+
+func (u *unaryExpr) String() string {
+	if u.op == "NOT" {
+		return fmt.Sprintf("NOT %s", u.operand)
+	}
+	return fmt.Sprintf("%s%s", u.op, u.operand)
+}
+
+func (e *isNullExpr) String() string {
+	if e.not {
+		return fmt.Sprintf("%s IS NOT NULL", e.operand)
+	}
+	return fmt.Sprintf("%s IS NULL", e.operand)
+}
+
+func (e *inExpr) String() string {
+	values := make([]string, len(e.values))
+	for i, value := range e.values {
+		values[i] = value.String()
+	}
+	op := "IN"
+	if e.not {
+		op = "NOT IN"
+	}
+	return fmt.Sprintf("%s %s (%s)", e.operand, op, strings.Join(values, ", "))
+}
+
+func (f *funcCall) String() string {
+	if f.arg == nil {
+		return f.name + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", f.name, f.arg)
+}
+
+// selectItem is an expression of the SELECT list, or * if expr is nil
+type selectItem struct {
+	expr  node
+	alias string
+}
+
+// tableRef is a table of the FROM or JOIN clauses
+type tableRef struct {
+	name  string
+	alias string
+}
+
+// joinClause joins a table on a condition
+type joinClause struct {
+	table tableRef
+	how   string
+	on    node
+}
+
+// orderItem is an expression of the ORDER BY clause
+type orderItem struct {
+	expr       node
+	descending bool
+}
+
+// selectStatement is a parsed SELECT query
+type selectStatement struct {
+	items   []selectItem
+	from    tableRef
+	joins   []joinClause
+	where   node
+	groupBy []node
+	having  node
+	orderBy []orderItem
+	// -1 if there is no limit
+	limit int
+}
+
+// parser builds a selectStatement from the tokens of a query
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses a SELECT query
+func parse(query string) (*selectStatement, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	return p.parseSelect()
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the keyword or symbol
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokenKeyword || t.kind == tokenSymbol) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes the next token, which must be the keyword or symbol
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("Expected %s", text)
+	}
+	return nil
+}
+
+// errorf returns an error at the position of the next token
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	found := "end of query"
+	if t.kind != tokenEOF {
+		found = fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Errorf("%s at position %d, found %s", fmt.Sprintf(format, args...), t.pos, found)
+}
+
+func (p *parser) identifier() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return "", p.errorf("Expected a name")
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *parser) parseSelect() (*selectStatement, error) {
+	statement := &selectStatement{limit: -1}
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+
+	for {
+		item := selectItem{}
+		if !p.accept("*") {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item.expr = expr
+			if p.accept("AS") {
+				if item.alias, err = p.identifier(); err != nil {
+					return nil, err
+				}
+			} else if p.peek().kind == tokenIdent {
+				item.alias = p.next().text
+			}
+		}
+		statement.items = append(statement.items, item)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	var err error
+	if statement.from, err = p.parseTable(); err != nil {
+		return nil, err
+	}
+
+	for {
+		join := joinClause{}
+		switch {
+		case p.accept("JOIN"):
+			join.how = "inner"
+		case p.accept("INNER"):
+			join.how = "inner"
+			err = p.expect("JOIN")
+		case p.accept("LEFT"):
+			join.how = "left"
+			p.accept("OUTER")
+			err = p.expect("JOIN")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if join.how == "" {
+			break
+		}
+
+		if join.table, err = p.parseTable(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("ON"); err != nil {
+			return nil, err
+		}
+		if join.on, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		statement.joins = append(statement.joins, join)
+	}
+
+	if p.accept("WHERE") {
+		if statement.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.accept("GROUP") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			statement.groupBy = append(statement.groupBy, expr)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if p.accept("HAVING") {
+		if statement.having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item := orderItem{expr: expr}
+			if p.accept("DESC") {
+				item.descending = true
+			} else {
+				p.accept("ASC")
+			}
+			statement.orderBy = append(statement.orderBy, item)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if p.accept("LIMIT") {
+		t := p.peek()
+		limit, err := strconv.Atoi(t.text)
+		if t.kind != tokenNumber || err != nil || limit < 0 {
+			return nil, p.errorf("Expected a row count")
+		}
+		p.pos++
+		statement.limit = limit
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("Unexpected token")
+	}
+	return statement, nil
+}
+
+func (p *parser) parseTable() (tableRef, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return tableRef{}, err
+	}
+	table := tableRef{name: name, alias: name}
+	if p.accept("AS") {
+		if table.alias, err = p.identifier(); err != nil {
+			return tableRef{}, err
+		}
+	} else if p.peek().kind == tokenIdent {
+		table.alias = p.next().text
+	}
+	return table, nil
+}
+
+// parseExpr parses an expression, from the lowest precedence OR down to single values
+func (p *parser) parseExpr() (node, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.accept("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "NOT", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokenSymbol && slices.Contains([]string{"=", "!=", "<>", "<", "<=", ">", ">="}, t.text):
+		p.pos++
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		op := t.text
+		if op == "<>" {
+			op = "!="
+		}
+		return &binaryExpr{op: op, left: left, right: right}, nil
+	case p.accept("IS"):
+		not := p.accept("NOT")
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{operand: left, not: not}, nil
+	}
+
+	not := p.accept("NOT")
+	switch {
+	case p.accept("IN"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		in := &inExpr{operand: left, not: not}
+		for {
+			value, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			in.values = append(in.values, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return in, nil
+	case p.accept("LIKE"):
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		var like node = &binaryExpr{op: "LIKE", left: left, right: right}
+		if not {
+			like = &unaryExpr{op: "NOT", operand: like}
+		}
+		return like, nil
+	case not:
+		return nil, p.errorf("Expected IN or LIKE after NOT")
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenSymbol || (t.text != "+" && t.text != "-") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenSymbol || (t.text != "*" && t.text != "/") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if l, ok := operand.(*literal); ok {
+			switch v := l.value.(type) {
+			case int:
+				return &literal{value: -v}, nil
+			case float64:
+				return &literal{value: -v}, nil
+			}
+		}
+		return &unaryExpr{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.pos++
+		if i, err := strconv.Atoi(t.text); err == nil {
+			return &literal{value: i}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("Invalid number")
+		}
+		return &literal{value: f}, nil
+	case tokenString:
+		p.pos++
+		return &literal{value: t.text}, nil
+	case tokenKeyword:
+		switch {
+		case p.accept("NULL"):
+			return &literal{value: nil}, nil
+		case p.accept("TRUE"):
+			return &literal{value: true}, nil
+		case p.accept("FALSE"):
+			return &literal{value: false}, nil
+		}
+	case tokenSymbol:
+		if p.accept("(") {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		}
+	case tokenIdent:
+		p.pos++
+		if p.accept("(") {
+			return p.parseFunction(t)
+		}
+		if p.accept(".") {
+			name, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			return &columnRef{table: t.text, name: name}, nil
+		}
+		return &columnRef{name: t.text}, nil
+	}
+	return nil, p.errorf("Expected an expression")
+}
+
+// parseFunction parses the arguments of an aggregate function after its opening parenthesis
+func (p *parser) parseFunction(name token) (node, error) {
+	call := &funcCall{name: strings.ToUpper(name.text)}
+	switch call.name {
+	case "SUM", "AVG", "MIN", "MAX", "COUNT":
+	default:
+		return nil, fmt.Errorf("Unknown function %s at position %d", name.text, name.pos)
+	}
+
+	if call.name == "COUNT" && p.accept("*") {
+		return call, p.expect(")")
+	}
+	arg, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	call.arg = arg
+	return call, p.expect(")")
+}
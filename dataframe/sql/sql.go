@@ -0,0 +1,695 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"teddy/dataframe"
+	"teddy/dataframe/aggregate"
+	"teddy/dataframe/expr"
+	"teddy/dataframe/filters"
+	"teddy/dataframe/series"
+)
+
+// Context holds the DataFrames that queries can use as tables
+type Context struct {
+	tables map[string]*dataframe.DataFrame
+}
+
+// NewContext returns a Context without tables
+func NewContext() *Context {
+	return &Context{tables: make(map[string]*dataframe.DataFrame)}
+}
+
+// Register makes the DataFrame available to queries as a table with the given name
+func (c *Context) Register(name string, df *dataframe.DataFrame) *Context {
+	c.tables[name] = df
+	return c
+}
+
+// relation is the intermediate result of a query.
+// Its columns are named "alias.column" after the table they come from.
+type relation struct {
+	df      *dataframe.DataFrame
+	aliases []string
+	// grouped is true after GROUP BY, when only the group columns and aggregates can be used
+	grouped bool
+	// aggregates maps an aggregate function call to the column holding its result
+	aggregates map[string]string
+}
+
+// Query runs a SELECT query against the registered tables and returns the result.
+//
+// Supported clauses are SELECT, FROM, [INNER | LEFT [OUTER]] JOIN ... ON, WHERE,
+// GROUP BY, HAVING, ORDER BY (by expression, output name or position) and LIMIT.
+// Expressions support arithmetic, comparisons, AND, OR, NOT, IS [NOT] NULL,
+// [NOT] IN, [NOT] LIKE and the aggregates COUNT, SUM, AVG, MIN and MAX.
+// Join conditions must be equalities between columns joined with AND.
+// WHERE and HAVING keep the rows where the condition is true: comparisons with NULL are unknown,
+// as in SQL, so neither they nor their NOT match.
+func (c *Context) Query(query string) (*dataframe.DataFrame, error) {
+	statement, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := c.table(statement.from, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, join := range statement.joins {
+		if rel, err = c.join(rel, join); err != nil {
+			return nil, err
+		}
+	}
+
+	if statement.where != nil {
+		if rel.df, err = rel.filter(statement.where); err != nil {
+			return nil, err
+		}
+	}
+
+	// Aggregate if there is a GROUP BY or an aggregate function
+	calls := []*funcCall{}
+	for _, item := range statement.items {
+		calls = collectCalls(item.expr, calls)
+	}
+	calls = collectCalls(statement.having, calls)
+	for _, item := range statement.orderBy {
+		calls = collectCalls(item.expr, calls)
+	}
+	if len(statement.groupBy) > 0 || len(calls) > 0 {
+		if rel, err = rel.group(statement.groupBy, calls); err != nil {
+			return nil, err
+		}
+	}
+
+	if statement.having != nil {
+		if !rel.grouped {
+			return nil, fmt.Errorf("HAVING requires GROUP BY or an aggregate function")
+		}
+		if rel.df, err = rel.filter(statement.having); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := rel.project(statement.items)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statement.orderBy) > 0 {
+		if result, err = rel.order(result, statement.orderBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if statement.limit >= 0 && statement.limit < result.Height() {
+		drop := make([]int, 0, result.Height()-statement.limit)
+		for i := statement.limit; i < result.Height(); i++ {
+			drop = append(drop, i)
+		}
+		result = result.DropRows(drop...)
+	}
+
+	return result, result.Err()
+}
+
+// table returns a relation for a registered table, with the columns named after its alias
+func (c *Context) table(ref tableRef, aliases []string) (*relation, error) {
+	df, ok := c.tables[ref.name]
+	if !ok {
+		return nil, fmt.Errorf("Table not found: %s", ref.name)
+	}
+	if slices.Contains(aliases, ref.alias) {
+		return nil, fmt.Errorf("Table name %s is used more than once, use AS to rename it", ref.alias)
+	}
+
+	if err := df.Err(); err != nil {
+		return nil, err
+	}
+
+	// The relation is a new DataFrame, so the registered one is never changed, even in place mode
+	columns := []series.SeriesInterface{}
+	for _, column := range df.ColumnNames() {
+		columns = append(columns, df.GetSeries(column).Rename(ref.alias+"."+column))
+	}
+	relationDF := dataframe.NewDataFrame(columns...).SetParallelism(df.Parallelism())
+	return &relation{df: relationDF, aliases: []string{ref.alias}}, relationDF.Err()
+}
+
+// join joins a table to the relation
+func (c *Context) join(left *relation, join joinClause) (*relation, error) {
+	right, err := c.table(join.table, left.aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each equality matches a column of the left relation with a column of the table.
+	// The table gets a copy of its key named like the left key, so both can be joined on the same name.
+	on := []string{}
+	for _, equality := range splitAnd(join.on) {
+		b, ok := equality.(*binaryExpr)
+		leftRef, leftOk := b.left.(*columnRef)
+		rightRef, rightOk := b.right.(*columnRef)
+		if !ok || b.op != "=" || !leftOk || !rightOk {
+			return nil, fmt.Errorf("JOIN ON only supports equalities between columns joined with AND, found %s", equality)
+		}
+
+		leftName, leftErr := left.resolve(leftRef)
+		rightName, rightErr := right.resolve(rightRef)
+		if leftErr != nil || rightErr != nil {
+			leftName, leftErr = left.resolve(rightRef)
+			rightName, rightErr = right.resolve(leftRef)
+		}
+		if leftErr != nil {
+			return nil, leftErr
+		}
+		if rightErr != nil {
+			return nil, rightErr
+		}
+
+		right.df = right.df.AddSeries(right.df.GetSeries(rightName).Rename(leftName))
+		on = append(on, leftName)
+	}
+
+	df := left.df.Join(right.df, on, dataframe.OptionsMap{"how": join.how})
+	return &relation{df: df, aliases: append(slices.Clone(left.aliases), right.aliases...)}, df.Err()
+}
+
+// splitAnd returns the conditions of a chain of ANDs
+func splitAnd(condition node) []node {
+	if b, ok := condition.(*binaryExpr); ok && b.op == "AND" {
+		return append(splitAnd(b.left), splitAnd(b.right)...)
+	}
+	return []node{condition}
+}
+
+// resolve returns the name of the column of the relation a reference points to
+func (r *relation) resolve(ref *columnRef) (string, error) {
+	matches := []string{}
+	for _, column := range r.df.ColumnNames() {
+		for _, alias := range r.aliases {
+			if (ref.table == "" || ref.table == alias) && column == alias+"."+ref.name {
+				matches = append(matches, column)
+			}
+		}
+	}
+
+	switch {
+	case len(matches) > 1:
+		return "", fmt.Errorf("Column %s is ambiguous, qualify it with a table name", ref)
+	case len(matches) == 0 && r.grouped:
+		return "", fmt.Errorf("Column %s must appear in GROUP BY or be used in an aggregate function", ref)
+	case len(matches) == 0:
+		return "", fmt.Errorf("%w: \"%s\"", dataframe.ErrColumnNotFound, ref)
+	}
+	return matches[0], nil
+}
+
+// compile converts a query expression to an expression over the columns of the relation
+func (r *relation) compile(e node) (*expr.Expr, error) {
+	switch e := e.(type) {
+	case *columnRef:
+		name, err := r.resolve(e)
+		if err != nil {
+			return nil, err
+		}
+		return expr.Col(name), nil
+	case *literal:
+		return expr.Lit(e.value), nil
+	case *funcCall:
+		name, ok := r.aggregates[e.String()]
+		if !ok {
+			return nil, fmt.Errorf("Aggregate function %s is not allowed here", e)
+		}
+		return expr.Col(name), nil
+	case *isNullExpr:
+		operand, err := r.compile(e.operand)
+		if err != nil {
+			return nil, err
+		}
+		if e.not {
+			return operand.IsNotNull(), nil
+		}
+		return operand.IsNull(), nil
+	case *inExpr:
+		operand, err := r.compile(e.operand)
+		if err != nil {
+			return nil, err
+		}
+		in := expr.Lit(false)
+		for _, value := range e.values {
+			compiled, err := r.compile(value)
+			if err != nil {
+				return nil, err
+			}
+			in = in.Or(operand.Eq(compiled))
+		}
+		if e.not {
+			return in.Not(), nil
+		}
+		return in, nil
+	case *unaryExpr:
+		operand, err := r.compile(e.operand)
+		if err != nil {
+			return nil, err
+		}
+		if e.op == "NOT" {
+			return operand.Not(), nil
+		}
+		return expr.Lit(0).Sub(operand).Alias(operand.Name()), nil
+	case *binaryExpr:
+		if e.op == "LIKE" {
+			return nil, fmt.Errorf("LIKE is only supported in WHERE and HAVING")
+		}
+		left, err := r.compile(e.left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.compile(e.right)
+		if err != nil {
+			return nil, err
+		}
+		switch e.op {
+		case "+":
+			return left.Add(right), nil
+		case "-":
+			return left.Sub(right), nil
+		case "*":
+			return left.Mul(right), nil
+		case "/":
+			return left.Div(right), nil
+		case "=":
+			return left.Eq(right), nil
+		case "!=":
+			return left.Ne(right), nil
+		case "<":
+			return left.Lt(right), nil
+		case "<=":
+			return left.Le(right), nil
+		case ">":
+			return left.Gt(right), nil
+		case ">=":
+			return left.Ge(right), nil
+		case "AND":
+			return left.And(right), nil
+		case "OR":
+			return left.Or(right), nil
+		}
+	}
+	return nil, fmt.Errorf("Unsupported expression %s", e)
+}
+
+// evaluate computes a query expression for each row of the relation
+func (r *relation) evaluate(e node) (series.SeriesInterface, error) {
+	compiled, err := r.compile(e)
+	if err != nil {
+		return nil, err
+	}
+	columns := []series.SeriesInterface{}
+	for _, name := range r.df.ColumnNames() {
+		columns = append(columns, r.df.GetSeries(name))
+	}
+	return compiled.Evaluate(columns, r.df.Height())
+}
+
+// filter returns the rows of the relation where the condition is true
+func (r *relation) filter(condition node) (*dataframe.DataFrame, error) {
+	mask, err := r.mask(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	drop := []int{}
+	for i, keep := range mask {
+		if !keep {
+			drop = append(drop, i)
+		}
+	}
+	return r.df.DropRows(drop...), nil
+}
+
+// mask returns whether the condition is true for each row
+func (r *relation) mask(condition node) ([]bool, error) {
+	mask, _, err := r.truth(condition)
+	return mask, err
+}
+
+// truth evaluates the condition with the three-valued logic of SQL, using the filters package.
+// Returns whether the condition is true for each row, and whether it is unknown because of a null.
+// Comparisons with null are unknown, and so are NOT, AND and OR of unknown unless the other
+// operand decides the result.
+func (r *relation) truth(condition node) ([]bool, []bool, error) {
+	height := r.df.Height()
+	mask := make([]bool, height)
+	unknown := make([]bool, height)
+
+	// fromIndexes sets the mask for the rows matched by filters.Apply, and marks the null rows of s as unknown.
+	// A nil s is never unknown.
+	fromIndexes := func(indexes []int, s series.SeriesInterface) ([]bool, []bool, error) {
+		for _, i := range indexes {
+			mask[i] = true
+		}
+		for i := range unknown {
+			unknown[i] = s != nil && s.IsNull(i)
+		}
+		return mask, unknown, nil
+	}
+
+	switch e := condition.(type) {
+	case *binaryExpr:
+		switch e.op {
+		case "AND", "OR":
+			left, leftUnknown, err := r.truth(e.left)
+			if err != nil {
+				return nil, nil, err
+			}
+			right, rightUnknown, err := r.truth(e.right)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := range mask {
+				if e.op == "AND" {
+					mask[i] = left[i] && right[i]
+					// False if either side is false, whatever the other side is
+					leftFalse := !left[i] && !leftUnknown[i]
+					rightFalse := !right[i] && !rightUnknown[i]
+					unknown[i] = !leftFalse && !rightFalse && (leftUnknown[i] || rightUnknown[i])
+				} else {
+					mask[i] = left[i] || right[i]
+					unknown[i] = !mask[i] && (leftUnknown[i] || rightUnknown[i])
+				}
+			}
+			return mask, unknown, nil
+		case "LIKE":
+			pattern, ok := e.right.(*literal)
+			if !ok {
+				return nil, nil, fmt.Errorf("LIKE requires a string pattern, found %s", e.right)
+			}
+			text, ok := pattern.value.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("LIKE requires a string pattern, found %s", e.right)
+			}
+			s, err := r.evaluate(e.left)
+			if err != nil {
+				return nil, nil, err
+			}
+			return fromIndexes(filters.Apply(s, filters.And(filters.IsNotNull(), like(text))), s)
+		case "=", "!=", "<", "<=", ">", ">=":
+			left, err := r.evaluate(e.left)
+			if err != nil {
+				return nil, nil, err
+			}
+			right, err := r.evaluate(e.right)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := range mask {
+				if left.IsNull(i) || right.IsNull(i) {
+					unknown[i] = true
+					continue
+				}
+				mask[i] = comparison(e.op, right.Get(i))(left.Get(i))
+			}
+			return mask, unknown, nil
+		}
+	case *unaryExpr:
+		if e.op == "NOT" {
+			operand, operandUnknown, err := r.truth(e.operand)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := range mask {
+				mask[i] = !operand[i] && !operandUnknown[i]
+			}
+			return mask, operandUnknown, nil
+		}
+	case *isNullExpr:
+		s, err := r.evaluate(e.operand)
+		if err != nil {
+			return nil, nil, err
+		}
+		if e.not {
+			return fromIndexes(filters.Apply(s, filters.IsNotNull()), nil)
+		}
+		return fromIndexes(filters.Apply(s, filters.IsNull()), nil)
+	case *inExpr:
+		s, err := r.evaluate(e.operand)
+		if err != nil {
+			return nil, nil, err
+		}
+		values := []any{}
+		hasNull := false
+		for _, value := range e.values {
+			l, ok := value.(*literal)
+			if !ok {
+				return nil, nil, fmt.Errorf("IN only supports constant values, found %s", value)
+			}
+			if l.value == nil {
+				hasNull = true
+				continue
+			}
+			values = append(values, l.value)
+		}
+		fromIndexes(filters.Apply(s, filters.And(filters.IsNotNull(), filters.In(values...))), s)
+		for i := range mask {
+			// A value that matches none of the values may still equal the null one
+			unknown[i] = unknown[i] || (!mask[i] && hasNull)
+			if e.not {
+				mask[i] = !mask[i] && !unknown[i]
+			}
+		}
+		return mask, unknown, nil
+	}
+
+	// Any other expression must give a bool for each row
+	s, err := r.evaluate(condition)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range mask {
+		value, ok := s.Get(i).(bool)
+		if !ok && !s.IsNull(i) {
+			return nil, nil, fmt.Errorf("Condition %s is not a boolean", condition)
+		}
+		mask[i] = value
+		unknown[i] = s.IsNull(i)
+	}
+	return mask, unknown, nil
+}
+
+// comparison returns the filter for a comparison with the value
+func comparison(op string, value any) filters.Filter {
+	switch op {
+	case "=":
+		return filters.Equal(value)
+	case "!=":
+		return filters.NotEqual(value)
+	case "<":
+		return filters.LessThan(value)
+	case "<=":
+		return filters.LessEqual(value)
+	case ">":
+		return filters.GreaterThan(value)
+	}
+	return filters.GreaterEqual(value)
+}
+
+// like returns a filter for a LIKE pattern, where % matches any text and _ matches a single character
+func like(pattern string) filters.Filter {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			builder.WriteString(".*")
+		case '_':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteString("$")
+	re := regexp.MustCompile(builder.String())
+
+	return func(value any) bool {
+		return re.MatchString(fmt.Sprint(value))
+	}
+}
+
+// collectCalls appends the aggregate function calls of an expression that are not in calls yet
+func collectCalls(e node, calls []*funcCall) []*funcCall {
+	switch e := e.(type) {
+	case *funcCall:
+		if !slices.ContainsFunc(calls, func(call *funcCall) bool { return call.String() == e.String() }) {
+			calls = append(calls, e)
+		}
+	case *binaryExpr:
+		calls = collectCalls(e.right, collectCalls(e.left, calls))
+	case *unaryExpr:
+		calls = collectCalls(e.operand, calls)
+	case *isNullExpr:
+		calls = collectCalls(e.operand, calls)
+	case *inExpr:
+		calls = collectCalls(e.operand, calls)
+	}
+	return calls
+}
+
+// group groups the relation by the columns and computes the aggregate function calls with aggregate.GroupBy
+func (r *relation) group(groupBy []node, calls []*funcCall) (*relation, error) {
+	input := dataframe.NewDataFrame()
+	by := []string{}
+	for _, e := range groupBy {
+		ref, ok := e.(*columnRef)
+		if !ok {
+			return nil, fmt.Errorf("GROUP BY only supports columns, found %s", e)
+		}
+		name, err := r.resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		by = append(by, name)
+		input = input.AddSeries(r.df.GetSeries(name))
+	}
+
+	// Each call aggregates its own copy of the argument, so a column can have several aggregates
+	aggregations := make(map[string]aggregate.Aggregator)
+	aggregates := make(map[string]string)
+	for i, call := range calls {
+		name := fmt.Sprintf("__aggregate%d", i)
+		argument := call.arg
+		if argument == nil {
+			argument = &literal{value: 1}
+		}
+		s, err := r.evaluate(argument)
+		if err != nil {
+			return nil, err
+		}
+		input = input.AddSeries(s.Rename(name))
+
+		switch call.name {
+		case "COUNT":
+			aggregations[name] = aggregate.Count()
+		case "SUM":
+			aggregations[name] = aggregate.Sum()
+		case "AVG":
+			aggregations[name] = aggregate.Mean()
+		case "MIN":
+			aggregations[name] = aggregate.Min()
+		case "MAX":
+			aggregations[name] = aggregate.Max()
+		}
+		aggregations[name] = skipNulls(aggregations[name])
+		aggregates[call.String()] = name
+	}
+
+	// Without GROUP BY the aggregates are one row even for no rows, such as a COUNT(*) of 0
+	if len(by) == 0 && input.Height() == 0 {
+		empty := dataframe.NewDataFrame()
+		for _, name := range aggregates {
+			empty = empty.AddSeries(series.NewSeries(name, []any{aggregations[name]()}))
+		}
+		return &relation{df: empty, aliases: r.aliases, grouped: true, aggregates: aggregates}, empty.Err()
+	}
+
+	df := aggregate.GroupBy(input, by, aggregations)
+	return &relation{df: df, aliases: r.aliases, grouped: true, aggregates: aggregates}, df.Err()
+}
+
+// skipNulls returns an aggregator that ignores null values, as SQL aggregates do
+func skipNulls(aggregator aggregate.Aggregator) aggregate.Aggregator {
+	return func(values ...any) any {
+		return aggregator(slices.DeleteFunc(slices.Clone(values), func(value any) bool { return value == nil })...)
+	}
+}
+
+// project computes the SELECT list for each row of the relation
+func (r *relation) project(items []selectItem) (*dataframe.DataFrame, error) {
+	result := dataframe.NewDataFrame()
+	add := func(s series.SeriesInterface, name string, qualified string) error {
+		if result.HasColumn(name) {
+			name = qualified
+		}
+		if result.HasColumn(name) {
+			return fmt.Errorf("Duplicate column name %s, use AS to rename it", name)
+		}
+		result = result.AddSeries(s.Rename(name))
+		return nil
+	}
+
+	for _, item := range items {
+		if item.expr == nil {
+			if r.grouped {
+				return nil, fmt.Errorf("SELECT * can't be used with GROUP BY or aggregate functions")
+			}
+			for _, column := range r.df.ColumnNames() {
+				_, name, _ := strings.Cut(column, ".")
+				if err := add(r.df.GetSeries(column), name, column); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		s, err := r.evaluate(item.expr)
+		if err != nil {
+			return nil, err
+		}
+		name := item.alias
+		qualified := item.alias
+		if name == "" {
+			name = item.expr.String()
+			qualified = name
+			if ref, ok := item.expr.(*columnRef); ok {
+				name = ref.name
+			}
+		}
+		if err := add(s, name, qualified); err != nil {
+			return nil, err
+		}
+	}
+	return result, result.Err()
+}
+
+// order sorts the result of the projection of the relation.
+// Items can be output column names, 1-based positions in the SELECT list, or expressions over the relation.
+func (r *relation) order(result *dataframe.DataFrame, items []orderItem) (*dataframe.DataFrame, error) {
+	keys := []string{}
+	descending := []bool{}
+	sortFrame := result
+	for i, item := range items {
+		var key series.SeriesInterface
+		switch e := item.expr.(type) {
+		case *literal:
+			position, ok := e.value.(int)
+			if !ok || position < 1 || position > result.Width() {
+				return nil, fmt.Errorf("ORDER BY position %s is not in the SELECT list", e)
+			}
+			key = result.GetSeries(result.ColumnNames()[position-1])
+		case *columnRef:
+			if e.table == "" && result.HasColumn(e.name) {
+				key = result.GetSeries(e.name)
+			}
+		}
+		if key == nil {
+			var err error
+			if key, err = r.evaluate(item.expr); err != nil {
+				return nil, err
+			}
+		}
+
+		name := fmt.Sprintf("__order%d", i)
+		sortFrame = sortFrame.AddSeries(key.Rename(name))
+		keys = append(keys, name)
+		descending = append(descending, item.descending)
+	}
+
+	sorted := sortFrame.Sort(keys, dataframe.OptionsMap{"descending": descending})
+	for _, key := range keys {
+		sorted = sorted.DropColumn(key)
+	}
+	return sorted, sorted.Err()
+}
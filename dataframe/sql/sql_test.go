@@ -0,0 +1,179 @@
+package sql_test
+
+import (
+	"slices"
+	"strings"
+	"teddy/dataframe"
+	"teddy/dataframe/series"
+	"teddy/dataframe/sql"
+	"testing"
+)
+
+// newContext returns a Context with an orders and a customers table
+func newContext() *sql.Context {
+	orders := dataframe.NewDataFrame(
+		series.NewIntSeries("id", []int{1, 2, 3, 4, 5}),
+		series.NewIntSeries("customer", []int{1, 2, 1, 3, 9}),
+		series.NewFloat64SeriesWithNulls("amount", []float64{10, 25, 40, 0, 5}, []bool{false, false, false, true, false}),
+	)
+	customers := dataframe.NewDataFrame(
+		series.NewIntSeries("customer", []int{1, 2, 3}),
+		series.NewStringSeries("name", []string{"Ann", "Bob", "Cid"}),
+		series.NewStringSeries("city", []string{"Paris", "Oslo", "Paris"}),
+	)
+	return sql.NewContext().Register("orders", orders).Register("customers", customers)
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		columns []string
+		rows    [][]any
+	}{
+		{
+			name:    "select where",
+			query:   "SELECT id, amount * 2 AS double FROM orders WHERE amount > 8 AND customer IN (1, 2)",
+			columns: []string{"id", "double"},
+			rows:    [][]any{{1, 20.0}, {2, 50.0}, {3, 80.0}},
+		},
+		{
+			name:    "star, is null",
+			query:   "select * from orders where amount is null",
+			columns: []string{"id", "customer", "amount"},
+			rows:    [][]any{{4, 3, nil}},
+		},
+		{
+			name:    "group by, having, order by position, limit",
+			query:   "SELECT customer, COUNT(*), SUM(amount) AS total FROM orders GROUP BY customer HAVING COUNT(*) >= 1 ORDER BY 3 DESC LIMIT 2",
+			columns: []string{"customer", "COUNT(*)", "total"},
+			rows:    [][]any{{1, 2, 50.0}, {2, 1, 25.0}},
+		},
+		{
+			name:    "aggregate without group by skips nulls",
+			query:   "SELECT COUNT(amount) AS n, MAX(amount) AS top FROM orders",
+			columns: []string{"n", "top"},
+			rows:    [][]any{{4, 40.0}},
+		},
+		{
+			name:    "aggregate without group by of no rows",
+			query:   "SELECT COUNT(*), COUNT(amount) AS n FROM orders WHERE id > 10",
+			columns: []string{"COUNT(*)", "n"},
+			rows:    [][]any{{0, 0}},
+		},
+		{
+			name:    "not of a comparison with null",
+			query:   "SELECT id FROM orders WHERE NOT amount > 8",
+			columns: []string{"id"},
+			rows:    [][]any{{5}},
+		},
+		{
+			name:    "not of unknown and true",
+			query:   "SELECT id FROM orders WHERE NOT (amount > 100 AND id = 4) AND id != 2",
+			columns: []string{"id"},
+			rows:    [][]any{{1}, {3}, {5}},
+		},
+		{
+			name:    "unknown or true",
+			query:   "SELECT id FROM orders WHERE amount > 30 OR id = 4",
+			columns: []string{"id"},
+			rows:    [][]any{{3}, {4}},
+		},
+		{
+			name:    "not in with null",
+			query:   "SELECT id FROM orders WHERE id NOT IN (1, NULL)",
+			columns: []string{"id"},
+			rows:    [][]any{},
+		},
+		{
+			name:    "quoted names and doubled quotes",
+			query:   "SELECT \"id\" AS `select`, 'it''s' AS s FROM orders WHERE id = 1",
+			columns: []string{"select", "s"},
+			rows:    [][]any{{1, "it's"}},
+		},
+		{
+			name:    "join",
+			query:   "SELECT o.id, c.name FROM orders o JOIN customers AS c ON o.customer = c.customer WHERE c.name LIKE 'A%' ORDER BY o.id DESC",
+			columns: []string{"id", "name"},
+			rows:    [][]any{{3, "Ann"}, {1, "Ann"}},
+		},
+		{
+			name:    "left join",
+			query:   "SELECT id, name FROM orders LEFT JOIN customers ON customers.customer = orders.customer WHERE id > 3",
+			columns: []string{"id", "name"},
+			rows:    [][]any{{4, "Cid"}, {5, nil}},
+		},
+		{
+			name:    "group by joined column",
+			query:   "SELECT city, AVG(amount) FROM orders JOIN customers ON orders.customer = customers.customer GROUP BY city ORDER BY city",
+			columns: []string{"city", "AVG(amount)"},
+			rows:    [][]any{{"Oslo", 25.0}, {"Paris", 25.0}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			df, err := newContext().Query(test.query)
+			if err != nil {
+				t.Fatalf("Error running query: %v", err)
+			}
+			if !slices.Equal(df.ColumnNames(), test.columns) {
+				t.Errorf("Expected columns %v, got %v", test.columns, df.ColumnNames())
+			}
+			if df.Height() != len(test.rows) {
+				t.Fatalf("Expected %d rows, got %d", len(test.rows), df.Height())
+			}
+			for i, row := range test.rows {
+				for j, column := range test.columns {
+					if got := df.GetSeries(column).Get(i); got != row[j] {
+						t.Errorf("Row %d column %s: expected %v, got %v", i, column, row[j], got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{"SELECT id FROM orders WHERE", "at position 27"},
+		{"SELECT id FROM orders WHERE amount > 'x", "Unterminated string at position 37"},
+		{"SELECT id FROM missing", "Table not found: missing"},
+		{"SELECT unknown FROM orders", "column not found"},
+		{"SELECT customer FROM orders JOIN customers ON orders.customer = customers.customer", "ambiguous"},
+		{"SELECT id, SUM(amount) FROM orders GROUP BY customer", "must appear in GROUP BY"},
+		{"SELECT id FROM orders ORDER BY 2", "ORDER BY position 2"},
+	}
+
+	for _, test := range tests {
+		_, err := newContext().Query(test.query)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Query %q: expected error containing %q, got %v", test.query, test.err, err)
+		}
+	}
+}
+
+func TestQueryInPlaceTable(t *testing.T) {
+	// Tests that queries don't change a registered DataFrame in place mode
+	df := dataframe.NewDataFrame(
+		series.NewIntSeries("x", []int{1, 2, 3}),
+		series.NewStringSeries("c", []string{"a", "b", "c"}),
+	).SetInPlace(true)
+	ctx := sql.NewContext().Register("t", df).Register("u", dataframe.NewDataFrame(series.NewIntSeries("x", []int{2})).SetInPlace(true))
+
+	for range 2 {
+		result, err := ctx.Query("SELECT t.x FROM t JOIN u ON t.x = u.x WHERE t.x > 1")
+		if err != nil {
+			t.Fatalf("Error running query: %v", err)
+		}
+		if !slices.Equal(result.GetSeries("x").Values(), []any{2}) {
+			t.Errorf("Expected [2], got %v", result.GetSeries("x").Values())
+		}
+	}
+	if !slices.Equal(df.ColumnNames(), []string{"x", "c"}) || df.Height() != 3 {
+		t.Errorf("Expected the table to be unchanged, got columns %v and height %d", df.ColumnNames(), df.Height())
+	}
+}
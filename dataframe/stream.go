@@ -0,0 +1,214 @@
+package dataframe
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Stream reads a CSV source as a sequence of DataFrames of up to chunkRows rows.
+// Only the rows of the current chunk are kept in memory, so sources larger than
+// memory can be processed one chunk at a time.
+//
+// All DataFrames have the same columns and types. With a Schema the columns are
+// parsed as in Load. Otherwise the types are inferred from the first rows when
+// inferdatatypes is set, or are strings. In both cases empty values are nulls,
+// except in string columns where they are empty strings.
+//
+// A value in a later chunk that doesn't match the type of its column stops the
+// stream with an error wrapping ErrSchemaMismatch, unless coerce is set.
+//
+//	for chunk, err := range dataframe.Read().FilePath("logs.csv").Option("header", true).Stream(100000) {
+//		if err != nil {
+//			return err
+//		}
+//		process(chunk)
+//	}
+//
+// Options (set with Option):
+//   - inferrows: int (default: chunkRows) Number of rows to infer the types from.
+//   - coerce: bool (default: false) Read values that don't match the type of a nullable column as nulls.
+func (dfr *DataFrameReader) Stream(chunkRows int) iter.Seq2[*DataFrame, error] {
+	return func(yield func(*DataFrame, error) bool) {
+		if err := dfr.streamCSV(chunkRows, yield); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// streamCSV yields the chunks of a CSV source until the end, or until yield returns false.
+// An error is returned instead of yielded.
+func (dfr *DataFrameReader) streamCSV(chunkRows int, yield func(*DataFrame, error) bool) error {
+	if chunkRows < 1 {
+		return fmt.Errorf("Chunk size must be positive, got %d", chunkRows)
+	}
+	options, err := dfr.options.standardizeOptions()
+	if err != nil {
+		return fmt.Errorf("Error standardizing options: %w", err)
+	}
+
+	fileType := dfr.fileType
+	if fileType == "" {
+		fileType = "csv"
+		if dfr.stringValue == "" {
+			fileType = detectFileType(dfr.filePath)
+		}
+	}
+	if fileType != "csv" {
+		return fmt.Errorf("Unsupported file type for streaming: %s", fileType)
+	}
+
+	var source io.Reader = strings.NewReader(dfr.stringValue)
+	if dfr.stringValue == "" {
+		file, err := os.Open(dfr.filePath)
+		if err != nil {
+			return fmt.Errorf("Error opening file: %s, %w", dfr.filePath, err)
+		}
+		defer file.Close()
+		source = file
+	}
+
+	csvReader := csv.NewReader(bufio.NewReader(source))
+	csvReader.Comma = options.GetDelimiter()
+	csvReader.TrimLeadingSpace = options.GetTrimLeadingSpace()
+
+	// readRows appends records until there are n rows, or the data ends
+	var pending [][]string
+	done := false
+	readRows := func(n int) error {
+		for !done && len(pending) < n {
+			record, err := csvReader.Read()
+			if err == io.EOF {
+				done = true
+			} else if err != nil {
+				return fmt.Errorf("Error reading CSV data: %w", err)
+			} else {
+				pending = append(pending, record)
+			}
+		}
+		return nil
+	}
+
+	// The first record is the header, otherwise the columns are named by position
+	if err := readRows(1); err != nil || len(pending) == 0 {
+		return err
+	}
+	headers := pending[0]
+	if options.GetHeader() {
+		pending = pending[:0]
+	} else {
+		headers = make([]string, len(pending[0]))
+		for i := range headers {
+			headers[i] = fmt.Sprintf("Column %d", i)
+		}
+	}
+
+	inferRows := options.GetInferRows()
+	if inferRows < 1 {
+		inferRows = chunkRows
+	}
+	if err := readRows(inferRows); err != nil {
+		return err
+	}
+	schema, colIndexes, err := streamSchema(headers, pending[:min(inferRows, len(pending))], options)
+	if err != nil {
+		return err
+	}
+
+	for row := 0; ; {
+		if err := readRows(chunkRows); err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		n := min(chunkRows, len(pending))
+		df, err := parseCSVChunk(pending[:n], schema, colIndexes, options.GetCoerce())
+		if err != nil {
+			return fmt.Errorf("%w: rows %d to %d: %w", ErrSchemaMismatch, row, row+n-1, err)
+		}
+		if !yield(df, nil) {
+			return nil
+		}
+		pending = slices.Delete(pending, 0, n)
+		row += n
+	}
+}
+
+// streamSchema returns the Schema of a stream and the position of each of its columns
+// in the records. Without a Schema in the options, the types are inferred from the sample rows.
+func streamSchema(headers []string, sample [][]string, options *Options) (*Schema, []int, error) {
+	columns := options.GetColumns()
+	if schema := options.GetSchema(); schema != nil {
+		// Columns are matched by name when there is a header, otherwise by position
+		fields, colIndexes := []Field{}, []int{}
+		for i, field := range schema.Fields {
+			if columns != nil && !slices.Contains(columns, field.Name) {
+				continue
+			}
+			colIdx := i
+			if options.GetHeader() {
+				colIdx = slices.Index(headers, field.Name)
+			}
+			if colIdx == -1 || colIdx >= len(headers) {
+				return nil, nil, columnNotFound(field.Name)
+			}
+			fields, colIndexes = append(fields, field), append(colIndexes, colIdx)
+		}
+		return &Schema{Fields: fields}, colIndexes, nil
+	}
+
+	if columns == nil {
+		columns = headers
+	}
+	fields, colIndexes := make([]Field, len(columns)), make([]int, len(columns))
+	for i, column := range columns {
+		colIndexes[i] = slices.Index(headers, column)
+		if colIndexes[i] == -1 {
+			return nil, nil, columnNotFound(column)
+		}
+		fields[i] = Field{Name: column, Type: "string", Nullable: true}
+
+		// Keep strings if a value of the sample can't be parsed as the inferred type
+		if options.GetInferDataTypes() {
+			values := columnValues(sample, colIndexes[i])
+			seriesType, err := inferType(values)
+			inferred := Field{Name: column, Type: schemaTypeName(schemaTypes[seriesType]), Nullable: true}
+			if _, parseErr := parseColumn(inferred, values, false); err == nil && parseErr == nil {
+				fields[i] = inferred
+			}
+		}
+	}
+	schema, err := NewSchema(fields...)
+	return schema, colIndexes, err
+}
+
+// parseCSVChunk parses the records into a DataFrame with the columns of the Schema
+func parseCSVChunk(records [][]string, schema *Schema, colIndexes []int, coerce bool) (*DataFrame, error) {
+	df := NewDataFrame()
+	for i, field := range schema.Fields {
+		s, err := parseColumn(field, columnValues(records, colIndexes[i]), coerce)
+		if err != nil {
+			return nil, err
+		}
+		df = df.AddSeries(s)
+	}
+	return df, nil
+}
+
+// columnValues returns the values of a column of the records, empty where a record is short
+func columnValues(records [][]string, colIdx int) []string {
+	values := make([]string, len(records))
+	for i, record := range records {
+		if colIdx < len(record) {
+			values[i] = record[colIdx]
+		}
+	}
+	return values
+}
@@ -0,0 +1,253 @@
+package dataframe
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// structField maps a struct field to a column
+type structField struct {
+	index  int
+	column string
+}
+
+// structFields returns the exported fields of a struct type and their column names.
+//
+// The column name is taken from the `df:"name"` tag, or the field name if there is no tag.
+// Fields tagged with `df:"-"` are skipped. Returns an error if two fields have the same column name.
+func structFields(typ reflect.Type) ([]structField, error) {
+	fields := []structField{}
+	fieldNames := map[string]string{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		column := field.Name
+		if tag, ok := field.Tag.Lookup("df"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				column = tag
+			}
+		}
+		if other, ok := fieldNames[column]; ok {
+			return nil, fmt.Errorf("Fields %s and %s have the same column name \"%s\"", other, field.Name, column)
+		}
+		fieldNames[column] = field.Name
+		fields = append(fields, structField{index: i, column: column})
+	}
+	return fields, nil
+}
+
+// FromStructs creates a DataFrame from a slice of structs or pointers to structs.
+//
+// Each exported field becomes a column, named by its `df:"name"` tag or the field name.
+// Integer fields become IntSeries, floats Float64Series, strings StringSeries, bools BoolSeries
+// and time.Time TimeSeries. Pointer fields are nullable, nil pointers become nulls.
+// Fields of other types are stored in a GenericSeries.
+func FromStructs(slice any) (*DataFrame, error) {
+	value := reflect.ValueOf(slice)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Expected a slice of structs, got %T", slice)
+	}
+
+	elemType := value.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Expected a slice of structs, got %T", slice)
+	}
+
+	fields, err := structFields(elemType)
+	if err != nil {
+		return nil, err
+	}
+
+	df := NewDataFrame()
+	for _, field := range fields {
+		values := make([]any, value.Len())
+		for i := range values {
+			elem := value.Index(i)
+			if isPointer {
+				if elem.IsNil() {
+					return nil, fmt.Errorf("Element %d is a nil pointer", i)
+				}
+				elem = elem.Elem()
+			}
+
+			fieldValue := elem.Field(field.index)
+			if fieldValue.Kind() == reflect.Pointer {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			values[i], err = normalizeFieldValue(fieldValue)
+			if err != nil {
+				return nil, fmt.Errorf("Element %d, field %s: %w", i, elemType.Field(field.index).Name, err)
+			}
+		}
+
+		fieldType := elemType.Field(field.index).Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		df.series = append(df.series, newSeriesOfType(field.column, seriesTypeOf(fieldType), values))
+	}
+
+	return df, nil
+}
+
+// ToStructs fills out, a pointer to a slice of structs or pointers to structs, with the rows of the DataFrame.
+//
+// Columns are matched to fields by the `df:"name"` tag or the field name.
+// Columns without a matching field and fields without a matching column are ignored.
+// Returns an error if a value can't be assigned to its field, or if a null value
+// is assigned to a field that is not a pointer.
+func (df *DataFrame) ToStructs(out any) error {
+	if df.err != nil {
+		return df.err
+	}
+
+	outValue := reflect.ValueOf(out)
+	if outValue.Kind() != reflect.Pointer || outValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Expected a pointer to a slice of structs, got %T", out)
+	}
+
+	sliceValue := outValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	structType := elemType
+	if isPointer {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("Expected a pointer to a slice of structs, got %T", out)
+	}
+
+	allFields, err := structFields(structType)
+	if err != nil {
+		return err
+	}
+	fields := []structField{}
+	for _, field := range allFields {
+		if df.HasColumn(field.column) {
+			fields = append(fields, field)
+		}
+	}
+
+	result := reflect.MakeSlice(sliceValue.Type(), df.Height(), df.Height())
+	for i := 0; i < df.Height(); i++ {
+		elem := reflect.New(structType).Elem()
+		for _, field := range fields {
+			s := df.GetSeries(field.column)
+			err := setField(elem.Field(field.index), s.Get(i))
+			if err != nil {
+				return fmt.Errorf("Row %d, column \"%s\": %w", i, field.column, err)
+			}
+		}
+
+		if isPointer {
+			result.Index(i).Set(elem.Addr())
+		} else {
+			result.Index(i).Set(elem)
+		}
+	}
+
+	sliceValue.Set(result)
+	return nil
+}
+
+// seriesTypeOf returns the type of the Series for a field type, or nil for a GenericSeries
+func seriesTypeOf(fieldType reflect.Type) reflect.Type {
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return intType
+	case reflect.Float32, reflect.Float64:
+		return float64Type
+	case reflect.String:
+		return stringType
+	case reflect.Bool:
+		return boolType
+	}
+	if fieldType == timeType {
+		return timeType
+	}
+	return nil
+}
+
+// normalizeFieldValue converts a field value to the value stored in its Series.
+// Returns an error if an unsigned value doesn't fit in an int.
+func normalizeFieldValue(value reflect.Value) (any, error) {
+	switch seriesTypeOf(value.Type()) {
+	case intType:
+		if value.CanInt() {
+			return int(value.Int()), nil
+		}
+		if value.Uint() > math.MaxInt {
+			return nil, fmt.Errorf("%w: value %v overflows int", ErrTypeConversion, value.Uint())
+		}
+		return int(value.Uint()), nil
+	case float64Type:
+		return value.Float(), nil
+	case stringType:
+		return value.String(), nil
+	case boolType:
+		return value.Bool(), nil
+	}
+	return value.Interface(), nil
+}
+
+// setField assigns a value from a Series to a struct field
+func setField(field reflect.Value, value any) error {
+	if value == nil {
+		if field.Kind() != reflect.Pointer {
+			return fmt.Errorf("%w: cannot assign null to field of type %s", ErrTypeConversion, field.Type())
+		}
+		field.SetZero()
+		return nil
+	}
+
+	if field.Kind() == reflect.Pointer {
+		target := reflect.New(field.Type().Elem())
+		if err := setField(target.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(target)
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch {
+	case field.CanInt() && v.CanInt():
+		if field.OverflowInt(v.Int()) {
+			return fmt.Errorf("value %v overflows field of type %s", value, field.Type())
+		}
+		field.SetInt(v.Int())
+	case field.CanUint() && v.CanInt():
+		if v.Int() < 0 || field.OverflowUint(uint64(v.Int())) {
+			return fmt.Errorf("value %v overflows field of type %s", value, field.Type())
+		}
+		field.SetUint(uint64(v.Int()))
+	case field.CanFloat() && (v.CanFloat() || v.CanInt()):
+		if v.CanInt() {
+			field.SetFloat(float64(v.Int()))
+		} else {
+			field.SetFloat(v.Float())
+		}
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(v)
+	case v.Kind() == field.Kind() && v.Type().ConvertibleTo(field.Type()):
+		field.Set(v.Convert(field.Type()))
+	default:
+		return fmt.Errorf("%w: cannot assign value of type %s to field of type %s", ErrTypeConversion, v.Type(), field.Type())
+	}
+	return nil
+}
@@ -3,7 +3,6 @@ package dataframe
 import (
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 )
 
@@ -29,20 +28,15 @@ func (options OptionsMap) getOption(key string, defaultValue any) any {
 	return defaultValue
 }
 
-func allSameType(values []any) bool {
-	if len(values) == 0 {
-		return true
-	}
-
-	firstType := fmt.Sprintf("%T", values[0])
-
-	for _, value := range values {
-		if fmt.Sprintf("%T", value) != firstType {
-			return false
+// Get returns the option with the given key in any case, or the default value if it isn't set.
+// It is meant for packages that take options, since the keys are not standardized.
+func (options OptionsMap) Get(key string, defaultValue any) any {
+	for k, v := range options {
+		if strings.EqualFold(k, key) {
+			return v
 		}
 	}
-
-	return true
+	return defaultValue
 }
 
 // FlattenInterface flattens a slice of slices of interfaces into a single slice of T
@@ -79,15 +73,8 @@ func flattenInterface[T any](acc []T, arr any) ([]T, error) {
 // InterfaceToTypeSlice flattens a slice of slices of interfaces into a single slice of T
 //
 // This can flatten [][]any into []T or []any into []T
-func InterfaceToTypeSlice[T any](values ...any) []T {
-	result, err := flattenInterface([]T{}, values)
-
-	if err != nil {
-		log.Println("Error flattening interface")
-		log.Fatal(err)
-	}
-
-	return result
+func InterfaceToTypeSlice[T any](values ...any) ([]T, error) {
+	return flattenInterface([]T{}, values)
 }
 
 // PadRight pads a string on the right with a pad string until it reaches a certain length
@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"teddy/dataframe"
+	"teddy/dataframe/filters"
+	"teddy/dataframe/series"
+)
+
+// Rule checks the values of a column and finds the rows that fail
+type Rule struct {
+	name    string
+	failing func(s series.SeriesInterface) []int
+	err     error
+}
+
+// Name returns the name of the rule used in the report
+func (r Rule) Name() string {
+	return r.name
+}
+
+// Satisfies returns a rule that fails for the non-null values that don't match the filter
+func Satisfies(name string, filter filters.Filter) Rule {
+	return Rule{
+		name: name,
+		failing: func(s series.SeriesInterface) []int {
+			return filters.Apply(s, filters.Not(filters.Or(filters.IsNull(), filter)))
+		},
+	}
+}
+
+// NotNull returns a rule that fails for null values
+func NotNull() Rule {
+	return Rule{
+		name: "not_null",
+		failing: func(s series.SeriesInterface) []int {
+			return filters.Apply(s, filters.IsNull())
+		},
+	}
+}
+
+// Unique returns a rule that fails for every row of a non-null value that appears more than once
+func Unique() Rule {
+	return Rule{
+		name: "unique",
+		failing: func(s series.SeriesInterface) []int {
+			counts := make(map[string]int)
+			for i := 0; i < s.Len(); i++ {
+				if !s.IsNull(i) {
+					counts[valueKey(s.Get(i))]++
+				}
+			}
+			return filters.Apply(s, func(value any) bool {
+				return value != nil && counts[valueKey(value)] > 1
+			})
+		},
+	}
+}
+
+// Range returns a rule that fails for values outside of min and max, inclusive.
+// A nil bound is not checked.
+func Range(min, max any) Rule {
+	filter := filters.And()
+	if min != nil {
+		filter = filters.And(filter, filters.GreaterEqual(min))
+	}
+	if max != nil {
+		filter = filters.And(filter, filters.LessEqual(max))
+	}
+	return Satisfies("range", filter)
+}
+
+// Regex returns a rule that fails for values that don't match the pattern.
+// Values that are not strings are matched against their string representation.
+func Regex(pattern string) Rule {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{name: "regex", err: fmt.Errorf("Invalid regex pattern \"%s\": %w", pattern, err)}
+	}
+	return Satisfies("regex", func(value any) bool {
+		return re.MatchString(fmt.Sprint(value))
+	})
+}
+
+// AllowedValues returns a rule that fails for values that are not in the set
+func AllowedValues(values ...any) Rule {
+	return Satisfies("allowed_values", filters.In(values...))
+}
+
+// check is a rule attached to a column, or an expression over a row if the column is empty
+type check struct {
+	column    string
+	rule      Rule
+	predicate func(row dataframe.Row) bool
+}
+
+// Validator holds the rules to check a DataFrame with
+type Validator struct {
+	checks []check
+}
+
+// New returns a Validator without rules
+func New() *Validator {
+	return &Validator{}
+}
+
+// Column attaches rules to a column
+func (v *Validator) Column(name string, rules ...Rule) *Validator {
+	for _, rule := range rules {
+		v.checks = append(v.checks, check{column: name, rule: rule})
+	}
+	return v
+}
+
+// Expression adds a rule over several columns that fails for the rows where the predicate is false
+func (v *Validator) Expression(name string, predicate func(row dataframe.Row) bool) *Validator {
+	v.checks = append(v.checks, check{rule: Rule{name: name}, predicate: predicate})
+	return v
+}
+
+// Validate checks the DataFrame with the rules and returns a report of the failing rows.
+//
+// The report has the columns "row", "column", "rule" and "value", with a row
+// for each failing value in the order the rules were added.
+// Expression rules have an empty column and a nil value.
+// Returns an error if a column doesn't exist or a rule is invalid.
+func (v *Validator) Validate(df *dataframe.DataFrame) (*dataframe.DataFrame, error) {
+	if err := df.Err(); err != nil {
+		return nil, err
+	}
+
+	rows := []int{}
+	columns := []string{}
+	rules := []string{}
+	values := []any{}
+
+	for _, c := range v.checks {
+		if c.rule.err != nil {
+			return nil, c.rule.err
+		}
+
+		if c.predicate != nil {
+			for i, row := range df.Rows() {
+				if !c.predicate(row) {
+					rows = append(rows, i)
+					columns = append(columns, "")
+					rules = append(rules, c.rule.name)
+					values = append(values, nil)
+				}
+			}
+			continue
+		}
+
+		s := df.GetSeries(c.column)
+		if s == nil {
+			return nil, fmt.Errorf("%w: \"%s\"", dataframe.ErrColumnNotFound, c.column)
+		}
+		for _, i := range c.rule.failing(s) {
+			rows = append(rows, i)
+			columns = append(columns, c.column)
+			rules = append(rules, c.rule.name)
+			values = append(values, s.Get(i))
+		}
+	}
+
+	return dataframe.NewDataFrame(
+		series.NewIntSeries("row", rows),
+		series.NewStringSeries("column", columns),
+		series.NewStringSeries("rule", rules),
+		series.NewGenericSeries("value", values),
+	), nil
+}
+
+// valueKey returns a key that is equal for equal values of the same type
+func valueKey(value any) string {
+	return fmt.Sprintf("%T|%v", value, value)
+}
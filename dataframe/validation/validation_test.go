@@ -0,0 +1,81 @@
+package validation_test
+
+import (
+	"errors"
+	"slices"
+	"teddy/dataframe"
+	"teddy/dataframe/filters"
+	"teddy/dataframe/series"
+	"teddy/dataframe/validation"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	// Tests that each rule reports the failing rows with the rule name and value
+	df := dataframe.FromRecords([]map[string]any{
+		{"id": 1, "email": "a@example.com", "age": 30, "country": "US", "start": 1, "end": 5},
+		{"id": 2, "email": "invalid", "age": 150, "country": "US", "start": 4, "end": 2},
+		{"id": 2, "age": 20, "country": "XX", "start": 1, "end": 1},
+	})
+
+	report, err := validation.New().
+		Column("id", validation.NotNull(), validation.Unique()).
+		Column("email", validation.NotNull(), validation.Regex(`^[^@]+@[^@]+$`)).
+		Column("age", validation.Range(0, 120)).
+		Column("country", validation.AllowedValues("US", "CA")).
+		Expression("start_before_end", func(row dataframe.Row) bool {
+			return row.Int("start") <= row.Int("end")
+		}).
+		Validate(df)
+	if err != nil {
+		t.Fatalf("Error validating: %v", err)
+	}
+
+	expectedRows := []int{1, 2, 2, 1, 1, 2, 1}
+	expectedRules := []string{"unique", "unique", "not_null", "regex", "range", "allowed_values", "start_before_end"}
+	expectedValues := []any{2, 2, nil, "invalid", 150, "XX", nil}
+
+	if report.Height() != len(expectedRows) {
+		t.Fatalf("Expected %d failures, got %d", len(expectedRows), report.Height())
+	}
+	for i := range expectedRows {
+		row := report.GetSeries("row").Get(i)
+		rule := report.GetSeries("rule").Get(i)
+		value := report.GetSeries("value").Get(i)
+		if row != expectedRows[i] || rule != expectedRules[i] || value != expectedValues[i] {
+			t.Errorf("Failure %d: expected %d %s %v, got %v %v %v", i, expectedRows[i], expectedRules[i], expectedValues[i], row, rule, value)
+		}
+	}
+}
+
+func TestValidateCustomRule(t *testing.T) {
+	// Tests building a rule from a filter, and that nulls are left to NotNull
+	df := dataframe.NewDataFrame(
+		series.NewSeries("name", []any{"Alice", nil, "bob"}),
+	)
+
+	report, err := validation.New().
+		Column("name", validation.Satisfies("capitalized", filters.Or(filters.StartsWith("A"), filters.StartsWith("B")))).
+		Validate(df)
+	if err != nil {
+		t.Fatalf("Error validating: %v", err)
+	}
+	if !slices.Equal(report.GetSeries("row").Values(), []any{2}) {
+		t.Errorf("Expected row 2 to fail, got %v", report.GetSeries("row").Values())
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	// Tests that missing columns and invalid rules are reported
+	df := dataframe.NewDataFrame(series.NewIntSeries("id", []int{1}))
+
+	_, err := validation.New().Column("missing", validation.NotNull()).Validate(df)
+	if !errors.Is(err, dataframe.ErrColumnNotFound) {
+		t.Errorf("Expected ErrColumnNotFound, got %v", err)
+	}
+
+	_, err = validation.New().Column("id", validation.Regex("[")).Validate(df)
+	if err == nil {
+		t.Errorf("Expected an error for an invalid regex")
+	}
+}
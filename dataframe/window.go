@@ -0,0 +1,217 @@
+package dataframe
+
+import (
+	"fmt"
+	"slices"
+	"teddy/dataframe/series"
+)
+
+// WindowFunction computes a value for each row from the rows of its partition, in order.
+// It is used with DataFrame.Window.
+type WindowFunction struct {
+	kind   string
+	column string
+	offset int
+	name   string
+}
+
+// RowNumber numbers the rows of each partition from 1
+func RowNumber() WindowFunction {
+	return WindowFunction{kind: "row_number", name: "row_number"}
+}
+
+// Rank numbers the rows of each partition from 1, giving equal rows the same rank and
+// leaving gaps after them, so ranks are 1, 1, 3
+func Rank() WindowFunction {
+	return WindowFunction{kind: "rank", name: "rank"}
+}
+
+// DenseRank is Rank without gaps, so ranks are 1, 1, 2
+func DenseRank() WindowFunction {
+	return WindowFunction{kind: "dense_rank", name: "dense_rank"}
+}
+
+// PercentRank is (rank - 1) / (rows in the partition - 1), from 0 to 1
+func PercentRank() WindowFunction {
+	return WindowFunction{kind: "percent_rank", name: "percent_rank"}
+}
+
+// Lag is the value of the column offset rows before, or null if there is no such row
+func Lag(column string, offset int) WindowFunction {
+	return WindowFunction{kind: "lag", column: column, offset: offset, name: column + "_lag"}
+}
+
+// Lead is the value of the column offset rows after, or null if there is no such row
+func Lead(column string, offset int) WindowFunction {
+	return WindowFunction{kind: "lead", column: column, offset: offset, name: column + "_lead"}
+}
+
+// FirstValue is the value of the column in the first row of the partition
+func FirstValue(column string) WindowFunction {
+	return WindowFunction{kind: "first_value", column: column, name: column + "_first"}
+}
+
+// LastValue is the value of the column in the last row of the partition
+func LastValue(column string) WindowFunction {
+	return WindowFunction{kind: "last_value", column: column, name: column + "_last"}
+}
+
+// CumSum is the sum of the column from the first row of the partition up to the row.
+// Null values are skipped.
+func CumSum(column string) WindowFunction {
+	return WindowFunction{kind: "cumsum", column: column, name: column + "_cumsum"}
+}
+
+// Alias returns the function with a new name for the resulting column
+func (f WindowFunction) Alias(name string) WindowFunction {
+	f.name = name
+	return f
+}
+
+// Window adds a column for each function, computed over partitions of the rows.
+//
+// Rows with the same values in the partitionBy columns form a partition, and each
+// partition is ordered by the orderBy columns, with nulls last. Without partitionBy
+// all rows are one partition. The rows of the DataFrame keep their order.
+//
+//	df.Window([]string{"customer"}, []string{"date"}, []WindowFunction{
+//		RowNumber(),
+//		Lag("amount", 1).Alias("previous_amount"),
+//	})
+//
+// Options:
+//   - descending: bool or []bool (default: false) Order in descending order, for all orderBy columns or for each one.
+func (df *DataFrame) Window(partitionBy, orderBy []string, functions []WindowFunction, options ...OptionsMap) *DataFrame {
+	if df.err != nil {
+		return df
+	}
+
+	descending, err := descendingOption(standardizeOptions(options...), len(orderBy))
+	if err != nil {
+		return df.withError(err)
+	}
+	columns := slices.Concat(partitionBy, orderBy)
+	for _, f := range functions {
+		if f.column != "" {
+			columns = append(columns, f.column)
+		}
+	}
+	if missing := df.findColumnsThatDontExist(columns); len(missing) > 0 {
+		return df.withError(columnNotFound(missing...))
+	}
+
+	// Group the rows into partitions and order each one
+	partitionKeys := df.seriesOf(partitionBy)
+	partitions := make(map[string][]int)
+	keys := []string{}
+	for row := 0; row < df.Height(); row++ {
+		key := rowKey(partitionKeys, row)
+		if _, ok := partitions[key]; !ok {
+			keys = append(keys, key)
+		}
+		partitions[key] = append(partitions[key], row)
+	}
+	compare := compareRows(df.seriesOf(orderBy), descending)
+	for _, key := range keys {
+		slices.SortStableFunc(partitions[key], compare)
+	}
+
+	result := df.target()
+	for _, f := range functions {
+		s, err := df.windowSeries(f, keys, partitions, compare)
+		if err != nil {
+			return df.withError(err)
+		}
+		result.setSeries(s.Rename(f.name))
+	}
+	return result
+}
+
+// windowSeries computes a window function over the ordered partitions
+func (df *DataFrame) windowSeries(f WindowFunction, keys []string, partitions map[string][]int, compare func(a, b int) int) (series.SeriesInterface, error) {
+	height := df.Height()
+	switch f.kind {
+	case "row_number", "rank", "dense_rank", "percent_rank":
+		ranks := make([]int, height)
+		percents := make([]float64, height)
+		for _, key := range keys {
+			rows := partitions[key]
+			rank, dense := 1, 1
+			for i, row := range rows {
+				if i > 0 && compare(rows[i-1], row) != 0 {
+					rank, dense = i+1, dense+1
+				}
+				switch f.kind {
+				case "row_number":
+					ranks[row] = i + 1
+				case "rank":
+					ranks[row] = rank
+				case "dense_rank":
+					ranks[row] = dense
+				}
+				if len(rows) > 1 {
+					percents[row] = float64(rank-1) / float64(len(rows)-1)
+				}
+			}
+		}
+		if f.kind == "percent_rank" {
+			return series.NewFloat64Series(f.name, percents), nil
+		}
+		return series.NewIntSeries(f.name, ranks), nil
+
+	case "cumsum":
+		return df.cumulativeSum(f.column, keys, partitions)
+	}
+
+	// The other functions take the value of another row of the partition, -1 for null
+	source := make([]int, height)
+	for _, key := range keys {
+		rows := partitions[key]
+		for i, row := range rows {
+			var j int
+			switch f.kind {
+			case "lag":
+				j = i - f.offset
+			case "lead":
+				j = i + f.offset
+			case "first_value":
+				j = 0
+			case "last_value":
+				j = len(rows) - 1
+			}
+			source[row] = -1
+			if j >= 0 && j < len(rows) {
+				source[row] = rows[j]
+			}
+		}
+	}
+	return takeRows(df.GetSeries(f.column), source), nil
+}
+
+// cumulativeSum sums the column over each ordered partition. An int column gives ints, otherwise float64.
+func (df *DataFrame) cumulativeSum(column string, keys []string, partitions map[string][]int) (series.SeriesInterface, error) {
+	s := df.GetSeries(column)
+	ints := make([]int, s.Len())
+	floats := make([]float64, s.Len())
+	for _, key := range keys {
+		intSum, floatSum := 0, 0.0
+		for _, row := range partitions[key] {
+			switch v := s.Get(row).(type) {
+			case nil:
+			case int:
+				intSum += v
+				floatSum += float64(v)
+			case float64:
+				floatSum += v
+			default:
+				return nil, fmt.Errorf("%w: column \"%s\" is not numeric", ErrTypeConversion, column)
+			}
+			ints[row], floats[row] = intSum, floatSum
+		}
+	}
+
+	if s.Type() == intType {
+		return series.NewIntSeries(column, ints), nil
+	}
+	return series.NewFloat64Series(column, floats), nil
+}
@@ -5,6 +5,7 @@ import (
 	"runtime"
 
 	"teddy/dataframe"
+	"teddy/dataframe/aggregate"
 	"teddy/dataframe/series"
 )
 
@@ -56,6 +57,11 @@ func main() {
 	memSavings := float64(m2.Alloc-m1.Alloc) / float64(m4.Alloc-m3.Alloc)
 	fmt.Printf("Typed Series uses %.2fx less memory than untyped Series\n", memSavings)
 
+	// Report the bytes held by each column, counting the boxing of untyped values
+	fmt.Println("\nMemory usage per column:")
+	untypedDF.MemoryUsage(true).PrintTable()
+	typedDF.MemoryUsage(true).PrintTable()
+
 	// Basic operations demonstration
 	fmt.Println("\nExample operations:")
 
@@ -86,7 +92,10 @@ func main() {
 	df.PrintTable()
 
 	// Group by and aggregate
-	groupedDF := df.GroupByIndex("Age", dataframe.Sum, "Salary", "Bonus")
+	groupedDF := aggregate.GroupBy(df, []string{"Age"}, map[string]aggregate.Aggregator{
+		"Salary": aggregate.Sum(),
+		"Bonus":  aggregate.Sum(),
+	}, dataframe.OptionsMap{"index": true})
 
 	fmt.Println("\nGrouped DataFrame (Sum of Salary and Bonus by Age):")
 	groupedDF.PrintTable()